@@ -0,0 +1,14 @@
+data "spectrocloud_cluster" "platform" {
+  name = "platform-eks"
+
+  # (optional) a cluster of another project
+  # project = "Platform"
+}
+
+output "kubeconfig" {
+  value = data.spectrocloud_cluster.platform.kubeconfig
+}
+
+output "health" {
+  value = data.spectrocloud_cluster.platform.health
+}
@@ -1,5 +1,7 @@
 data "spectrocloud_cluster_profile" "profile1" {
   name = "niktest_profile"
+  # (optional) defaults to the latest version
+  # version = "1.0.0"
 }
 
 output "same" {
@@ -0,0 +1,12 @@
+data "spectrocloud_clusters" "prod" {
+  tags   = ["env:prod"]
+  status = "Running"
+
+  # (optional)
+  # cloud_type = "eks"
+  # name_regex = "^payments-"
+}
+
+output "prod_clusters" {
+  value = [for cluster in data.spectrocloud_clusters.prod.clusters : cluster.name]
+}
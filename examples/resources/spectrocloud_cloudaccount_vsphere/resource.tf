@@ -0,0 +1,8 @@
+resource "spectrocloud_cloudaccount_vsphere" "account" {
+  name                          = "vsphere-dev"
+  private_cloud_gateway_id      = var.private_cloud_gateway_id
+  vsphere_vcenter               = var.vsphere_vcenter
+  vsphere_username              = var.vsphere_username
+  vsphere_password              = var.vsphere_password
+  vsphere_ignore_insecure_error = false
+}
@@ -77,6 +77,18 @@ resource "spectrocloud_cluster_aws" "cluster" {
     count         = 1
     instance_type = "t3.large"
     azs           = ["us-west-2a"]
+
+    additional_labels = {
+      "workload" = "batch"
+    }
+    additional_tags = {
+      "team" = "data"
+    }
+    taints {
+      key    = "workload"
+      value  = "batch"
+      effect = "NoSchedule"
+    }
   }
 
 }
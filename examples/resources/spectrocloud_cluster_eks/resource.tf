@@ -65,6 +65,8 @@ resource "spectrocloud_cluster_eks" "cluster" {
   machine_pool {
     name          = "worker-basic"
     count         = 1
+    min           = 1
+    max           = 3
     instance_type = "t3.large"
     az_subnets = {
       "us-west-2a" = "subnet-0d4978ddbff16c"
@@ -53,7 +53,11 @@ data "spectrocloud_pack" "ubuntu" {
 }
 
 resource "spectrocloud_cluster_profile" "profile" {
-  name        = "vsphere-picard-3"
+  name = "vsphere-picard-3"
+  # (optional) defaults to 1.0.0, changing packs creates the next patch version
+  # unless a new version is set here. Name, tags and description are updated
+  # in place on the latest version, earlier versions keep their own.
+  version     = "1.0.0"
   description = "basic cp"
   tags        = ["dev", "department:devops", "owner:bob"]
   cloud       = "vsphere"
@@ -67,10 +71,11 @@ resource "spectrocloud_cluster_profile" "profile" {
   }
 
   pack {
-    name   = "kubernetes"
-    tag    = "1.18.16"
-    uid    = data.spectrocloud_pack.k8s.id
-    values = data.spectrocloud_pack.k8s.values
+    name         = "kubernetes"
+    tag          = "1.18.16"
+    uid          = data.spectrocloud_pack.k8s.id
+    registry_uid = data.spectrocloud_pack.k8s.registry_uid
+    values       = data.spectrocloud_pack.k8s.values
   }
 
   pack {
@@ -102,6 +107,14 @@ resource "spectrocloud_cluster_profile" "profile" {
           name: wordpress
       EOT
     }
+    manifest {
+      name = "manifest-wordpress"
+      file = "${path.module}/manifests/wordpress.yaml"
+    }
+    manifest {
+      name      = "manifest-monitoring"
+      directory = "${path.module}/manifests/monitoring"
+    }
     #uid    = "spectro-manifest-pack"
   }
 }
@@ -0,0 +1,3 @@
+# by uid, name or <project_name>:<name>
+terraform import spectrocloud_cluster_vsphere.cluster 5fd0ca727c411c71b55a359c
+terraform import spectrocloud_cluster_vsphere.cluster Default:vsphere-dev
@@ -0,0 +1,2 @@
+# <private_cloud_gateway_id>:<ip_pool_id>
+terraform import spectrocloud_privatecloudgateway_ippool.ippool 5fd0ca727c411c71b55a359c:5fd0ca727c411c71b55a35a1
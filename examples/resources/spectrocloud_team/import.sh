@@ -0,0 +1,3 @@
+# by uid or by name
+terraform import spectrocloud_team.team 5fd0ca727c411c71b55a359c
+terraform import spectrocloud_team.team dev-team
@@ -13,6 +13,7 @@ require (
 	github.com/robfig/cron v1.2.0
 	github.com/spectrocloud/gomi v1.9.1-0.20210519044035-5333c9359877
 	github.com/spectrocloud/hapi v1.14.1-0.20211008142225-a25ce038cd52
+	gopkg.in/yaml.v2 v2.4.0
 )
 
 //replace github.com/spectrocloud/hapi => ../hapi
@@ -1,23 +1,27 @@
 package client
 
 import (
+	"context"
+
+	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client/herr"
+
 	"github.com/spectrocloud/hapi/models"
 	userC "github.com/spectrocloud/hapi/user/client/v1"
 )
 
-func (h *V1Client) ListBackupStorageLocation(projectScope bool) ([]*models.V1UserAssetsLocation, error) {
-	client, err := h.getUserClient()
+func (h *V1Client) ListBackupStorageLocation(ctx context.Context, projectScope bool) ([]*models.V1UserAssetsLocation, error) {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := userC.NewV1UsersAssetsLocationGetParams()
+	params := userC.NewV1UsersAssetsLocationGetParamsWithContext(ctx)
 	if projectScope {
-		params.WithContext(h.ctx)
+		params.WithContext(h.projectContext(ctx))
 	}
 	response, err := client.V1UsersAssetsLocationGet(params)
 	if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	bsls := make([]*models.V1UserAssetsLocation, len(response.Payload.Items))
@@ -28,16 +32,16 @@ func (h *V1Client) ListBackupStorageLocation(projectScope bool) ([]*models.V1Use
 	return bsls, nil
 }
 
-func (h *V1Client) GetBackupStorageLocation(uid string) (*models.V1UserAssetsLocation, error) {
-	client, err := h.getUserClient()
+func (h *V1Client) GetBackupStorageLocation(ctx context.Context, uid string) (*models.V1UserAssetsLocation, error) {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := userC.NewV1UsersAssetsLocationGetParamsWithContext(h.ctx)
+	params := userC.NewV1UsersAssetsLocationGetParamsWithContext(h.projectContext(ctx))
 	response, err := client.V1UsersAssetsLocationGet(params)
 	if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	for _, account := range response.Payload.Items {
@@ -49,56 +53,56 @@ func (h *V1Client) GetBackupStorageLocation(uid string) (*models.V1UserAssetsLoc
 	return nil, nil
 }
 
-func (h *V1Client) GetS3BackupStorageLocation(uid string) (*models.V1UserAssetsLocationS3, error) {
-	client, err := h.getUserClient()
+func (h *V1Client) GetS3BackupStorageLocation(ctx context.Context, uid string) (*models.V1UserAssetsLocationS3, error) {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := userC.NewV1UsersAssetsLocationS3GetParamsWithContext(h.ctx).WithUID(uid)
+	params := userC.NewV1UsersAssetsLocationS3GetParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	if response, err := client.V1UsersAssetsLocationS3Get(params); err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	} else {
 		return response.Payload, nil
 	}
 }
 
-func (h *V1Client) CreateS3BackupStorageLocation(bsl *models.V1UserAssetsLocationS3) (string, error) {
-	client, err := h.getUserClient()
+func (h *V1Client) CreateS3BackupStorageLocation(ctx context.Context, bsl *models.V1UserAssetsLocationS3) (string, error) {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := userC.NewV1UsersAssetsLocationS3CreateParamsWithContext(h.ctx).WithBody(bsl)
+	params := userC.NewV1UsersAssetsLocationS3CreateParamsWithContext(h.projectContext(ctx)).WithBody(bsl)
 	if resp, err := client.V1UsersAssetsLocationS3Create(params); err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	} else {
 		return *resp.Payload.UID, nil
 	}
 }
 
-func (h *V1Client) UpdateS3BackupStorageLocation(uid string, bsl *models.V1UserAssetsLocationS3) error {
-	client, err := h.getUserClient()
+func (h *V1Client) UpdateS3BackupStorageLocation(ctx context.Context, uid string, bsl *models.V1UserAssetsLocationS3) error {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	params := userC.NewV1UsersAssetsLocationS3UpdateParamsWithContext(h.ctx).WithUID(uid).WithBody(bsl)
+	params := userC.NewV1UsersAssetsLocationS3UpdateParamsWithContext(h.projectContext(ctx)).WithUID(uid).WithBody(bsl)
 	if _, err := client.V1UsersAssetsLocationS3Update(params); err != nil {
-		return err
+		return herr.Wrap(err)
 	}
 	return nil
 }
 
-func (h *V1Client) DeleteS3BackupStorageLocation(uid string) error {
-	client, err := h.getUserClient()
+func (h *V1Client) DeleteS3BackupStorageLocation(ctx context.Context, uid string) error {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	params := userC.NewV1UsersAssetsLocationS3DeleteParamsWithContext(h.ctx).WithUID(uid)
+	params := userC.NewV1UsersAssetsLocationS3DeleteParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	if _, err := client.V1UsersAssetsLocationS3Delete(params); err != nil {
-		return err
+		return herr.Wrap(err)
 	}
 	return nil
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"sync"
 	"time"
 
 	openapiclient "github.com/go-openapi/runtime/client"
@@ -18,65 +20,120 @@ import (
 
 	clusterC "github.com/spectrocloud/hapi/spectrocluster/client/v1"
 	userC "github.com/spectrocloud/hapi/user/client/v1"
+
+	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client/herr"
 )
 
 const (
 	//UriTemplate    string = "%s:%s"
 	authTokenInput string = "header"
 	authTokenKey   string = "Authorization"
+	apiKeyKey      string = "ApiKey"
 )
 
-var hubbleUri string
-
-var AuthClient authC.ClientService
-
-var schemes []string
-
-var authToken *AuthToken
 var tokenExpiry = 10 * time.Minute
 
-//var hubbleRestClientLatch sync.Mutex
-
 type AuthToken struct {
 	token  *models.V1UserToken
 	expiry time.Time
 }
 
 type V1Client struct {
-	ctx      context.Context
-	email    string
-	password string
+	projectUID string
+	email      string
+	password   string
+	apikey     string
+
+	// host and auth state are per client so that multiple provider
+	// configurations (aliases) never share a token or an endpoint
+	hubbleUri  string
+	schemes    []string
+	authClient authC.ClientService
+
+	retryPolicy       RetryPolicy
+	requestsPerSecond int
+	trace             *httpTrace
+
+	// transport is shared by every runtime of this client so connections
+	// are pooled and the rate limit applies across all calls
+	transport http.RoundTripper
+
+	tokenLock sync.Mutex
+	authToken *AuthToken
 }
 
-func New(hubbleHost, email, password, projectUID string) *V1Client {
-	ctx := context.Background()
-	if projectUID != "" {
-		ctx = GetProjectContextWithCtx(ctx, projectUID)
+// Option configures optional behaviour of a V1Client
+type Option func(*V1Client)
+
+// WithRetryPolicy overrides DefaultRetryPolicy for all calls made by the client
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(h *V1Client) {
+		h.retryPolicy = policy
 	}
+}
 
-	hubbleUri = hubbleHost
-	schemes = []string{"https"}
-	authHttpTransport := hapitransport.New(hubbleUri, "", schemes)
-	authHttpTransport.RetryAttempts = 0
-	//authHttpTransport.Debug = true
-	AuthClient = authC.New(authHttpTransport, strfmt.Default)
-	return &V1Client{ctx, email, password}
+// WithRateLimit caps the client at requestsPerSecond API calls, 0 means unlimited
+func WithRateLimit(requestsPerSecond int) Option {
+	return func(h *V1Client) {
+		h.requestsPerSecond = requestsPerSecond
+	}
+}
+
+// WithHttpTraceFile appends every request and response, redacted, as JSON lines
+// to the file at path
+func WithHttpTraceFile(path string) Option {
+	trace := &httpTrace{path: path}
+	return func(h *V1Client) {
+		h.trace = trace
+	}
 }
 
-func (h *V1Client) getNewAuthToken() (*AuthToken, error) {
+func New(hubbleHost, email, password, apikey, projectUID string, opts ...Option) *V1Client {
+	h := &V1Client{
+		projectUID:  projectUID,
+		email:       email,
+		password:    password,
+		apikey:      apikey,
+		hubbleUri:   hubbleHost,
+		schemes:     []string{"https"},
+		retryPolicy: DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	// every attempt of a retried request is logged on its own
+	h.transport = newRetryTransport(newRateLimitTransport(newLogTransport(base, h.trace), h.requestsPerSecond), h.retryPolicy)
+
+	authHttpTransport := h.newRuntime()
+	h.authClient = authC.New(authHttpTransport, strfmt.Default)
+	return h
+}
+
+// newRuntime returns an unauthenticated hapi runtime for the client host.
+// Retries are handled by our own round tripper rather than by hapi.
+func (h *V1Client) newRuntime() *hapitransport.Runtime {
+	httpTransport := hapitransport.New(h.hubbleUri, "", h.schemes)
+	httpTransport.Transport = h.transport
+	httpTransport.RetryAttempts = 0
+	return httpTransport
+}
+
+func (h *V1Client) getNewAuthToken(ctx context.Context) (*AuthToken, error) {
 	//httpClient, err := certs.GetHttpClient()
 	//if err != nil {
 	//	return nil, err
 	//}
-	authParam := authC.NewV1AuthenticateParams().
+	authParam := authC.NewV1AuthenticateParamsWithContext(ctx).
 		WithBody(&models.V1AuthLogin{
 			EmailID:  h.email,
 			Password: strfmt.Password(h.password),
 		})
-	res, err := AuthClient.V1Authenticate(authParam)
+	res, err := h.authClient.V1Authenticate(authParam)
 	if err != nil {
 		log.Error("Error", err)
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	if len(res.Payload.Authorization) == 0 {
@@ -84,23 +141,45 @@ func (h *V1Client) getNewAuthToken() (*AuthToken, error) {
 		return nil, errors.New(errMsg)
 	}
 
-	authToken = &AuthToken{
+	return &AuthToken{
 		token:  res.Payload,
 		expiry: time.Now().Add(tokenExpiry),
+	}, nil
+}
+
+// getAuthToken returns the cached token of this client, refreshing it when it
+// is missing or expired. Concurrent callers share a single refresh.
+func (h *V1Client) getAuthToken(ctx context.Context) (*AuthToken, error) {
+	h.tokenLock.Lock()
+	defer h.tokenLock.Unlock()
+
+	if h.authToken == nil || h.authToken.expiry.Before(time.Now()) {
+		tkn, err := h.getNewAuthToken(ctx)
+		if err != nil {
+			log.Error("Failed to get auth token ", err)
+			return nil, err
+		}
+		h.authToken = tkn
 	}
-	return authToken, nil
+
+	return h.authToken, nil
 }
 
-func (h *V1Client) GetProjectUID(projectName string) (string, error) {
-	client, err := h.getUserClient()
+// ProjectUID returns the project the client is scoped to, empty for tenant scope
+func (h *V1Client) ProjectUID() string {
+	return h.projectUID
+}
+
+func (h *V1Client) GetProjectUID(ctx context.Context, projectName string) (string, error) {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := userC.NewV1ProjectsListParamsWithContext(h.ctx)
+	params := userC.NewV1ProjectsListParamsWithContext(h.projectContext(ctx))
 	projects, err := client.V1ProjectsList(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	for _, project := range projects.Payload.Items {
@@ -112,6 +191,27 @@ func (h *V1Client) GetProjectUID(projectName string) (string, error) {
 	return "", fmt.Errorf("project '%s' not found", projectName)
 }
 
+type projectKey struct{}
+
+// ContextWithProject scopes the calls made with ctx to the project with the
+// given uid rather than to the project of the client
+func ContextWithProject(ctx context.Context, projectUID string) context.Context {
+	return context.WithValue(ctx, projectKey{}, projectUID)
+}
+
+// projectContext scopes ctx to the project set by ContextWithProject or else
+// to the project of the client, if any
+func (h *V1Client) projectContext(ctx context.Context) context.Context {
+	projectUID := h.projectUID
+	if uid, ok := ctx.Value(projectKey{}).(string); ok {
+		projectUID = uid
+	}
+	if projectUID == "" {
+		return ctx
+	}
+	return GetProjectContextWithCtx(ctx, projectUID)
+}
+
 func GetProjectContextWithCtx(c context.Context, projectUid string) context.Context {
 	return context.WithValue(c, hapitransport.CUSTOM_HEADERS, hapitransport.Values{
 		HeaderMap: map[string]string{
@@ -119,26 +219,24 @@ func GetProjectContextWithCtx(c context.Context, projectUid string) context.Cont
 		}})
 }
 
-func (h *V1Client) getTransport() (*hapitransport.Runtime, error) {
-	if authToken == nil || authToken.expiry.Before(time.Now()) {
-		if tkn, err := h.getNewAuthToken(); err != nil {
-			log.Error("Failed to get auth token ", err)
+func (h *V1Client) getTransport(ctx context.Context) (*hapitransport.Runtime, error) {
+	httpTransport := h.newRuntime()
+	if h.apikey != "" {
+		// api keys are sent as is, no login round trip is needed
+		httpTransport.DefaultAuthentication = openapiclient.APIKeyAuth(apiKeyKey, authTokenInput, h.apikey)
+	} else {
+		authToken, err := h.getAuthToken(ctx)
+		if err != nil {
 			return nil, err
-		} else {
-			authToken = tkn
 		}
+		httpTransport.DefaultAuthentication = openapiclient.APIKeyAuth(authTokenKey, authTokenInput, authToken.token.Authorization)
 	}
-
-	httpTransport := hapitransport.New(hubbleUri, "", schemes)
-	httpTransport.DefaultAuthentication = openapiclient.APIKeyAuth(authTokenKey, authTokenInput, authToken.token.Authorization)
-	httpTransport.RetryAttempts = 0
-	//httpTransport.Debug = true
 	return httpTransport, nil
 }
 
 // Clients
-func (h *V1Client) getClusterClient() (clusterC.ClientService, error) {
-	httpTransport, err := h.getTransport()
+func (h *V1Client) getClusterClient(ctx context.Context) (clusterC.ClientService, error) {
+	httpTransport, err := h.getTransport(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -146,8 +244,8 @@ func (h *V1Client) getClusterClient() (clusterC.ClientService, error) {
 	return clusterC.New(httpTransport, strfmt.Default), nil
 }
 
-func (h *V1Client) getUserClient() (userC.ClientService, error) {
-	httpTransport, err := h.getTransport()
+func (h *V1Client) getUserClient(ctx context.Context) (userC.ClientService, error) {
+	httpTransport, err := h.getTransport(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// rejectingServer answers every call with 401
+func rejectingServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = fmt.Fprint(w, `{"code": "Unauthorized", "message": "invalid credentials"}`)
+	}))
+}
+
+// TestClientMethodsReturnErrors calls every method of the client while logins,
+// api keys or connections fail, and checks that each one reports the failure
+func TestClientMethodsReturnErrors(t *testing.T) {
+	rejecting := rejectingServer()
+	defer rejecting.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	clients := []struct {
+		name   string
+		client *V1Client
+	}{
+		{"login rejected", newTestClient(t, rejecting.URL, "user@example.com", "")},
+		{"api key rejected", newTestClient(t, rejecting.URL, "", "api-key")},
+		{"connection refused", newTestClient(t, closed.URL, "user@example.com", "")},
+	}
+
+	for _, tc := range clients {
+		t.Run(tc.name, func(t *testing.T) {
+			value := reflect.ValueOf(tc.client)
+			called := 0
+			for i := 0; i < value.NumMethod(); i++ {
+				method := value.Type().Method(i)
+				fn := value.Method(i)
+				if fn.Type().NumOut() == 0 || fn.Type().Out(fn.Type().NumOut()-1) != errorType {
+					continue
+				}
+
+				called++
+				if panicked, err := callWithTestArgs(fn); panicked != nil {
+					t.Errorf("%s panicked: %v", method.Name, panicked)
+				} else if err == nil {
+					t.Errorf("%s returned no error", method.Name)
+				}
+			}
+			if called == 0 {
+				t.Fatal("no client method was called")
+			}
+		})
+	}
+}
+
+// callWithTestArgs calls fn with placeholder arguments and returns what it
+// panicked with or else its error
+func callWithTestArgs(fn reflect.Value) (panicked interface{}, err error) {
+	defer func() {
+		panicked = recover()
+	}()
+
+	args := make([]reflect.Value, fn.Type().NumIn())
+	for i := range args {
+		in := fn.Type().In(i)
+		switch {
+		case in == contextType:
+			args[i] = reflect.ValueOf(context.Background())
+		case in.Kind() == reflect.String:
+			args[i] = reflect.ValueOf("test").Convert(in)
+		case in.Kind() == reflect.Ptr:
+			args[i] = reflect.New(in.Elem())
+		default:
+			args[i] = reflect.Zero(in)
+		}
+	}
+
+	out := fn.Call(args)
+	if last := out[len(out)-1]; !last.IsNil() {
+		return nil, last.Interface().(error)
+	}
+	return nil, nil
+}
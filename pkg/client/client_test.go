@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/go-openapi/strfmt"
+	authC "github.com/spectrocloud/hapi/auth/client/v1"
+)
+
+// newTestClient returns a client for the API served at serverURL, retries are
+// disabled unless opts set a policy
+func newTestClient(t *testing.T, serverURL, email, apikey string, opts ...Option) *V1Client {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts = append([]Option{WithRetryPolicy(RetryPolicy{})}, opts...)
+	h := New(u.Host, email, "password", apikey, "", opts...)
+	h.schemes = []string{u.Scheme}
+	h.authClient = authC.New(h.newRuntime(), strfmt.Default)
+	return h
+}
+
+// fakeHubble is an API server that hands out a single token and serves one
+// project, recording who logged in and which tokens were presented
+type fakeHubble struct {
+	token      string
+	projectUID string
+
+	lock   sync.Mutex
+	logins []string
+	tokens []string
+}
+
+func (f *fakeHubble) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.URL.Path {
+	case "/v1/auth/authenticate":
+		var login struct {
+			EmailID string `json:"emailId"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&login)
+		f.lock.Lock()
+		f.logins = append(f.logins, login.EmailID)
+		f.lock.Unlock()
+		_, _ = fmt.Fprintf(w, `{"Authorization": %q}`, f.token)
+	case "/v1/projects":
+		f.lock.Lock()
+		f.tokens = append(f.tokens, r.Header.Get(authTokenKey))
+		f.lock.Unlock()
+		_, _ = fmt.Fprintf(w, `{"items": [{"metadata": {"name": "default", "uid": %q}}]}`, f.projectUID)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = fmt.Fprint(w, `{"code": "ResourceNotFound", "message": "not found"}`)
+	}
+}
+
+func TestClientsAreIsolated(t *testing.T) {
+	fakeA := &fakeHubble{token: "token-a", projectUID: "project-a"}
+	fakeB := &fakeHubble{token: "token-b", projectUID: "project-b"}
+	serverA := httptest.NewServer(fakeA)
+	defer serverA.Close()
+	serverB := httptest.NewServer(fakeB)
+	defer serverB.Close()
+
+	clientA := newTestClient(t, serverA.URL, "a@example.com", "")
+	clientB := newTestClient(t, serverB.URL, "b@example.com", "")
+
+	const calls = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*calls)
+	for i := 0; i < calls; i++ {
+		for _, tc := range []struct {
+			client *V1Client
+			want   string
+		}{
+			{clientA, "project-a"},
+			{clientB, "project-b"},
+		} {
+			wg.Add(1)
+			go func(client *V1Client, want string) {
+				defer wg.Done()
+				uid, err := client.GetProjectUID(context.Background(), "default")
+				if err != nil {
+					errs <- err
+				} else if uid != want {
+					errs <- fmt.Errorf("got project %s, want %s", uid, want)
+				}
+			}(tc.client, tc.want)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	for _, tc := range []struct {
+		name  string
+		fake  *fakeHubble
+		email string
+	}{
+		{"a", fakeA, "a@example.com"},
+		{"b", fakeB, "b@example.com"},
+	} {
+		if len(tc.fake.logins) != 1 || tc.fake.logins[0] != tc.email {
+			t.Errorf("server %s: got logins %v, want a single login of %s", tc.name, tc.fake.logins, tc.email)
+		}
+		if len(tc.fake.tokens) != calls {
+			t.Errorf("server %s: got %d calls, want %d", tc.name, len(tc.fake.tokens), calls)
+		}
+		for _, token := range tc.fake.tokens {
+			if token != tc.fake.token {
+				t.Errorf("server %s: got token %q, want %q", tc.name, token, tc.fake.token)
+			}
+		}
+	}
+}
@@ -1,40 +1,54 @@
 package client
 
 import (
+	"context"
+
+	"fmt"
 	"strings"
 
 	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client/herr"
 
-	hapitransport "github.com/spectrocloud/hapi/apiutil/transport"
 	"github.com/spectrocloud/hapi/models"
 
 	clusterC "github.com/spectrocloud/hapi/spectrocluster/client/v1"
 )
 
-func (h *V1Client) DeleteCluster(uid string) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) DeleteCluster(ctx context.Context, uid string) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1SpectroClustersDeleteParamsWithContext(h.ctx).WithUID(uid)
+	params := clusterC.NewV1SpectroClustersDeleteParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	_, err = client.V1SpectroClustersDelete(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) GetCluster(uid string) (*models.V1SpectroCluster, error) {
-	client, err := h.getClusterClient()
+// ForceDeleteCluster deletes the cluster without waiting for its cloud resources to be cleaned up
+func (h *V1Client) ForceDeleteCluster(ctx context.Context, uid string) error {
+	client, err := h.getClusterClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	forceDelete := true
+	params := clusterC.NewV1SpectroClustersDeleteParamsWithContext(h.projectContext(ctx)).WithUID(uid).WithForceDelete(&forceDelete)
+	_, err = client.V1SpectroClustersDelete(params)
+	return herr.Wrap(err)
+}
+
+func (h *V1Client) GetCluster(ctx context.Context, uid string) (*models.V1SpectroCluster, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1SpectroClustersGetParamsWithContext(h.ctx).WithUID(uid)
+	params := clusterC.NewV1SpectroClustersGetParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	success, err := client.V1SpectroClustersGet(params)
-	if e, ok := err.(*hapitransport.TransportError); ok && e.HttpCode == 404 {
-		// TODO(saamalik) check with team if this is proper?
+	if herr.IsNotFound(err) {
 		return nil, nil
 	} else if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	// special check if the cluster is marked deleted
@@ -46,150 +60,216 @@ func (h *V1Client) GetCluster(uid string) (*models.V1SpectroCluster, error) {
 	return success.Payload, nil
 }
 
-func (h *V1Client) GetClusterKubeConfig(uid string) (string, error) {
-	client, err := h.getClusterClient()
+// clusterListPageSize is the number of clusters fetched per list call
+var clusterListPageSize = int64(50)
+
+// GetClusters returns every cluster that is not deleted, following the pages of the list
+func (h *V1Client) GetClusters(ctx context.Context) ([]*models.V1SpectroCluster, error) {
+	client, err := h.getClusterClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make([]*models.V1SpectroCluster, 0)
+	continueToken := ""
+	for {
+		params := clusterC.NewV1SpectroClustersListParamsWithContext(h.projectContext(ctx)).WithLimit(&clusterListPageSize)
+		if continueToken != "" {
+			params = params.WithContinue(&continueToken)
+		}
+		page, err := client.V1SpectroClustersList(params)
+		if err != nil {
+			return nil, herr.Wrap(err)
+		}
+
+		for _, cluster := range page.Payload.Items {
+			if cluster.Status == nil || cluster.Status.State != "Deleted" {
+				clusters = append(clusters, cluster)
+			}
+		}
+
+		if page.Payload.Listmeta == nil || page.Payload.Listmeta.Continue == "" {
+			return clusters, nil
+		}
+		continueToken = page.Payload.Listmeta.Continue
+	}
+}
+
+func (h *V1Client) GetClusterByName(ctx context.Context, name string) (*models.V1SpectroCluster, error) {
+	clusters, err := h.GetClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cluster := range clusters {
+		if cluster.Metadata.Name == name {
+			return cluster, nil
+		}
+	}
+
+	return nil, fmt.Errorf("cluster '%s' not found", name)
+}
+
+func (h *V1Client) GetClusterKubeConfig(ctx context.Context, uid string) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
 	builder := new(strings.Builder)
-	params := clusterC.NewV1SpectroClustersUIDKubeConfigParamsWithContext(h.ctx).WithUID(uid)
+	params := clusterC.NewV1SpectroClustersUIDKubeConfigParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	_, err = client.V1SpectroClustersUIDKubeConfig(params, builder)
 	if err != nil {
 		if herr.IsNotFound(err) {
 			return "", nil
 		}
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return builder.String(), nil
 }
 
-func (h *V1Client) GetClusterImportManifest(uid string) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetClusterImportManifest(ctx context.Context, uid string) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
 	builder := new(strings.Builder)
-	params := clusterC.NewV1SpectroClustersUIDInstallerManifestParamsWithContext(h.ctx).WithUID(uid)
+	params := clusterC.NewV1SpectroClustersUIDInstallerManifestParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	_, err = client.V1SpectroClustersUIDInstallerManifest(params, builder)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return builder.String(), nil
 }
 
-func (h *V1Client) UpdateClusterProfileValues(uid string, profiles *models.V1SpectroClusterProfiles) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) UpdateClusterProfileValues(ctx context.Context, uid string, profiles *models.V1SpectroClusterProfiles) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
 	resolveNotification := true
-	params := clusterC.NewV1SpectroClustersUpdateProfilesParamsWithContext(h.ctx).WithUID(uid).
+	params := clusterC.NewV1SpectroClustersUpdateProfilesParamsWithContext(h.projectContext(ctx)).WithUID(uid).
 		WithBody(profiles).WithResolveNotification(&resolveNotification)
 	_, err = client.V1SpectroClustersUpdateProfiles(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) GetClusterBackupConfig(uid string) (*models.V1ClusterBackup, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetClusterBackupConfig(ctx context.Context, uid string) (*models.V1ClusterBackup, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1ClusterFeatureBackupGetParamsWithContext(h.ctx).WithUID(uid)
+	params := clusterC.NewV1ClusterFeatureBackupGetParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	success, err := client.V1ClusterFeatureBackupGet(params)
 	if err != nil {
 		if herr.IsNotFound(err) || herr.IsBackupNotConfigured(err) {
 			return nil, nil
 		}
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return success.Payload, nil
 }
 
-func (h *V1Client) CreateClusterBackupConfig(uid string, config *models.V1ClusterBackupConfig) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateClusterBackupConfig(ctx context.Context, uid string, config *models.V1ClusterBackupConfig) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	params := clusterC.NewV1ClusterFeatureBackupCreateParamsWithContext(h.ctx).WithUID(uid).WithBody(config)
+	params := clusterC.NewV1ClusterFeatureBackupCreateParamsWithContext(h.projectContext(ctx)).WithUID(uid).WithBody(config)
 	_, err = client.V1ClusterFeatureBackupCreate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) UpdateClusterBackupConfig(uid string, config *models.V1ClusterBackupConfig) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) UpdateClusterBackupConfig(ctx context.Context, uid string, config *models.V1ClusterBackupConfig) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	params := clusterC.NewV1ClusterFeatureBackupUpdateParamsWithContext(h.ctx).WithUID(uid).WithBody(config)
+	params := clusterC.NewV1ClusterFeatureBackupUpdateParamsWithContext(h.projectContext(ctx)).WithUID(uid).WithBody(config)
 	_, err = client.V1ClusterFeatureBackupUpdate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) ApplyClusterBackupConfig(uid string, config *models.V1ClusterBackupConfig) error {
-	if policy, err := h.GetClusterBackupConfig(uid); err != nil {
-		return err
+func (h *V1Client) ApplyClusterBackupConfig(ctx context.Context, uid string, config *models.V1ClusterBackupConfig) error {
+	if policy, err := h.GetClusterBackupConfig(ctx, uid); err != nil {
+		return herr.Wrap(err)
 	} else if policy == nil {
-		return h.CreateClusterBackupConfig(uid, config)
+		return h.CreateClusterBackupConfig(ctx, uid, config)
 	} else {
-		return h.UpdateClusterBackupConfig(uid, config)
+		return h.UpdateClusterBackupConfig(ctx, uid, config)
+	}
+}
+
+// CreateClusterBackupOnDemand starts a backup of the cluster and returns the uid of the backup request
+func (h *V1Client) CreateClusterBackupOnDemand(ctx context.Context, uid string, config *models.V1ClusterBackupConfig) (string, error) {
+	client, err := h.getClusterClient(ctx)
+	if err != nil {
+		return "", err
 	}
+
+	params := clusterC.NewV1ClusterFeatureBackupOnDemandCreateParamsWithContext(h.projectContext(ctx)).WithUID(uid).WithBody(config)
+	success, err := client.V1ClusterFeatureBackupOnDemandCreate(params)
+	if err != nil {
+		return "", herr.Wrap(err)
+	}
+
+	return *success.Payload.UID, nil
 }
 
-func (h *V1Client) GetClusterScanConfig(uid string) (*models.V1ClusterComplianceScan, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetClusterScanConfig(ctx context.Context, uid string) (*models.V1ClusterComplianceScan, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1ClusterFeatureComplianceScanGetParamsWithContext(h.ctx).WithUID(uid)
+	params := clusterC.NewV1ClusterFeatureComplianceScanGetParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	success, err := client.V1ClusterFeatureComplianceScanGet(params)
 	if err != nil {
 		if herr.IsNotFound(err) {
 			return nil, nil
 		}
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return success.Payload, nil
 }
 
-func (h *V1Client) CreateClusterScanConfig(uid string, config *models.V1ClusterComplianceScheduleConfig) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateClusterScanConfig(ctx context.Context, uid string, config *models.V1ClusterComplianceScheduleConfig) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	params := clusterC.NewV1ClusterFeatureComplianceScanCreateParamsWithContext(h.ctx).WithUID(uid).WithBody(config)
+	params := clusterC.NewV1ClusterFeatureComplianceScanCreateParamsWithContext(h.projectContext(ctx)).WithUID(uid).WithBody(config)
 	_, err = client.V1ClusterFeatureComplianceScanCreate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) UpdateClusterScanConfig(uid string, config *models.V1ClusterComplianceScheduleConfig) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) UpdateClusterScanConfig(ctx context.Context, uid string, config *models.V1ClusterComplianceScheduleConfig) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	params := clusterC.NewV1ClusterFeatureComplianceScanUpdateParamsWithContext(h.ctx).WithUID(uid).WithBody(config)
+	params := clusterC.NewV1ClusterFeatureComplianceScanUpdateParamsWithContext(h.projectContext(ctx)).WithUID(uid).WithBody(config)
 	_, err = client.V1ClusterFeatureComplianceScanUpdate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) ApplyClusterScanConfig(uid string, config *models.V1ClusterComplianceScheduleConfig) error {
-	if policy, err := h.GetClusterScanConfig(uid); err != nil {
-		return err
+func (h *V1Client) ApplyClusterScanConfig(ctx context.Context, uid string, config *models.V1ClusterComplianceScheduleConfig) error {
+	if policy, err := h.GetClusterScanConfig(ctx, uid); err != nil {
+		return herr.Wrap(err)
 	} else if policy == nil {
-		return h.CreateClusterScanConfig(uid, config)
+		return h.CreateClusterScanConfig(ctx, uid, config)
 	} else {
-		return h.UpdateClusterScanConfig(uid, config)
+		return h.UpdateClusterScanConfig(ctx, uid, config)
 	}
 }
@@ -1,76 +1,93 @@
 package client
 
 import (
-	hapitransport "github.com/spectrocloud/hapi/apiutil/transport"
+	"context"
+
+	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client/herr"
+
 	"github.com/spectrocloud/hapi/models"
 
 	clusterC "github.com/spectrocloud/hapi/spectrocluster/client/v1"
 )
 
-func (h *V1Client) CreateClusterAks(cluster *models.V1SpectroAzureClusterEntity) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateClusterAks(ctx context.Context, cluster *models.V1SpectroAzureClusterEntity) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := clusterC.NewV1SpectroClustersAksCreateParamsWithContext(h.ctx).WithBody(cluster)
+	params := clusterC.NewV1SpectroClustersAksCreateParamsWithContext(h.projectContext(ctx)).WithBody(cluster)
 	success, err := client.V1SpectroClustersAksCreate(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
 }
 
-func (h *V1Client) CreateMachinePoolAks(cloudConfigId string, machinePool *models.V1AzureMachinePoolConfigEntity) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateMachinePoolAks(ctx context.Context, cloudConfigId string, machinePool *models.V1AzureMachinePoolConfigEntity) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsAksMachinePoolCreateParamsWithContext(h.ctx).WithConfigUID(cloudConfigId).WithBody(machinePool)
+	params := clusterC.NewV1CloudConfigsAksMachinePoolCreateParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithBody(machinePool)
 	_, err = client.V1CloudConfigsAksMachinePoolCreate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) UpdateMachinePoolAks(cloudConfigId string, machinePool *models.V1AzureMachinePoolConfigEntity) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) UpdateMachinePoolAks(ctx context.Context, cloudConfigId string, machinePool *models.V1AzureMachinePoolConfigEntity) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsAksMachinePoolUpdateParamsWithContext(h.ctx).
+	params := clusterC.NewV1CloudConfigsAksMachinePoolUpdateParamsWithContext(h.projectContext(ctx)).
 		WithConfigUID(cloudConfigId).
 		WithMachinePoolName(*machinePool.PoolConfig.Name).
 		WithBody(machinePool)
 	_, err = client.V1CloudConfigsAksMachinePoolUpdate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) DeleteMachinePoolAks(cloudConfigId string, machinePoolName string) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) DeleteMachinePoolAks(ctx context.Context, cloudConfigId string, machinePoolName string) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsAksMachinePoolDeleteParamsWithContext(h.ctx).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
+	params := clusterC.NewV1CloudConfigsAksMachinePoolDeleteParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
 	_, err = client.V1CloudConfigsAksMachinePoolDelete(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) GetCloudConfigAks(configUID string) (*models.V1AzureCloudConfig, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetMachinePoolMachinesAks(ctx context.Context, cloudConfigId string, machinePoolName string) ([]*models.V1AzureMachine, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1CloudConfigsAksGetParamsWithContext(h.ctx).WithConfigUID(configUID)
+	params := clusterC.NewV1CloudConfigsAksPoolMachinesListParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
+	success, err := client.V1CloudConfigsAksPoolMachinesList(params)
+	if err != nil {
+		return nil, herr.Wrap(err)
+	}
+
+	return success.Payload.Items, nil
+}
+
+func (h *V1Client) GetCloudConfigAks(ctx context.Context, configUID string) (*models.V1AzureCloudConfig, error) {
+	client, err := h.getClusterClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := clusterC.NewV1CloudConfigsAksGetParamsWithContext(h.projectContext(ctx)).WithConfigUID(configUID)
 	success, err := client.V1CloudConfigsAksGet(params)
-	if e, ok := err.(*hapitransport.TransportError); ok && e.HttpCode == 404 {
-		// TODO(saamalik) check with team if this is proper?
+	if herr.IsNotFound(err) {
 		return nil, nil
 	} else if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return success.Payload, nil
@@ -1,131 +1,148 @@
 package client
 
 import (
-	hapitransport "github.com/spectrocloud/hapi/apiutil/transport"
+	"context"
+
+	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client/herr"
+
 	"github.com/spectrocloud/hapi/models"
 
 	clusterC "github.com/spectrocloud/hapi/spectrocluster/client/v1"
 )
 
-func (h *V1Client) CreateClusterAws(cluster *models.V1SpectroAwsClusterEntity) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateClusterAws(ctx context.Context, cluster *models.V1SpectroAwsClusterEntity) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := clusterC.NewV1SpectroClustersAwsCreateParamsWithContext(h.ctx).WithBody(cluster)
+	params := clusterC.NewV1SpectroClustersAwsCreateParamsWithContext(h.projectContext(ctx)).WithBody(cluster)
 	success, err := client.V1SpectroClustersAwsCreate(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
 }
 
-func (h *V1Client) CreateMachinePoolAws(cloudConfigId string, machinePool *models.V1AwsMachinePoolConfigEntity) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateMachinePoolAws(ctx context.Context, cloudConfigId string, machinePool *models.V1AwsMachinePoolConfigEntity) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsAwsMachinePoolCreateParamsWithContext(h.ctx).WithConfigUID(cloudConfigId).WithBody(machinePool)
+	params := clusterC.NewV1CloudConfigsAwsMachinePoolCreateParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithBody(machinePool)
 	_, err = client.V1CloudConfigsAwsMachinePoolCreate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) UpdateMachinePoolAws(cloudConfigId string, machinePool *models.V1AwsMachinePoolConfigEntity) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) UpdateMachinePoolAws(ctx context.Context, cloudConfigId string, machinePool *models.V1AwsMachinePoolConfigEntity) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsAwsMachinePoolUpdateParamsWithContext(h.ctx).
+	params := clusterC.NewV1CloudConfigsAwsMachinePoolUpdateParamsWithContext(h.projectContext(ctx)).
 		WithConfigUID(cloudConfigId).
 		WithMachinePoolName(*machinePool.PoolConfig.Name).
 		WithBody(machinePool)
 	_, err = client.V1CloudConfigsAwsMachinePoolUpdate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) DeleteMachinePoolAws(cloudConfigId string, machinePoolName string) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) DeleteMachinePoolAws(ctx context.Context, cloudConfigId string, machinePoolName string) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsAwsMachinePoolDeleteParamsWithContext(h.ctx).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
+	params := clusterC.NewV1CloudConfigsAwsMachinePoolDeleteParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
 	_, err = client.V1CloudConfigsAwsMachinePoolDelete(params)
-	return err
+	return herr.Wrap(err)
+}
+
+func (h *V1Client) GetMachinePoolMachinesAws(ctx context.Context, cloudConfigId string, machinePoolName string) ([]*models.V1AwsMachine, error) {
+	client, err := h.getClusterClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := clusterC.NewV1CloudConfigsAwsPoolMachinesListParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
+	success, err := client.V1CloudConfigsAwsPoolMachinesList(params)
+	if err != nil {
+		return nil, herr.Wrap(err)
+	}
+
+	return success.Payload.Items, nil
 }
 
 // Cloud Account
 
-func (h *V1Client) CreateCloudAccountAws(account *models.V1AwsAccount) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateCloudAccountAws(ctx context.Context, account *models.V1AwsAccount) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := clusterC.NewV1CloudAccountsAwsCreateParamsWithContext(h.ctx).WithBody(account)
+	params := clusterC.NewV1CloudAccountsAwsCreateParamsWithContext(h.projectContext(ctx)).WithBody(account)
 	success, err := client.V1CloudAccountsAwsCreate(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
 }
 
-func (h *V1Client) UpdateCloudAccountAws(account *models.V1AwsAccount) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) UpdateCloudAccountAws(ctx context.Context, account *models.V1AwsAccount) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
 	uid := account.Metadata.UID
-	params := clusterC.NewV1CloudAccountsAwsUpdateParamsWithContext(h.ctx).WithUID(uid).WithBody(account)
+	params := clusterC.NewV1CloudAccountsAwsUpdateParamsWithContext(h.projectContext(ctx)).WithUID(uid).WithBody(account)
 	_, err = client.V1CloudAccountsAwsUpdate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) DeleteCloudAccountAws(uid string) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) DeleteCloudAccountAws(ctx context.Context, uid string) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudAccountsAwsDeleteParamsWithContext(h.ctx).WithUID(uid)
+	params := clusterC.NewV1CloudAccountsAwsDeleteParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	_, err = client.V1CloudAccountsAwsDelete(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) GetCloudAccountAws(uid string) (*models.V1AwsAccount, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetCloudAccountAws(ctx context.Context, uid string) (*models.V1AwsAccount, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1CloudAccountsAwsGetParamsWithContext(h.ctx).WithUID(uid)
+	params := clusterC.NewV1CloudAccountsAwsGetParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	success, err := client.V1CloudAccountsAwsGet(params)
-	if e, ok := err.(*hapitransport.TransportError); ok && e.HttpCode == 404 {
-		// TODO(saamalik) check with team if this is proper?
+	if herr.IsNotFound(err) {
 		return nil, nil
 	} else if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return success.Payload, nil
 }
 
-func (h *V1Client) GetCloudAccountsAws() ([]*models.V1AwsAccount, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetCloudAccountsAws(ctx context.Context) ([]*models.V1AwsAccount, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1CloudAccountsAwsListParamsWithContext(h.ctx)
+	params := clusterC.NewV1CloudAccountsAwsListParamsWithContext(h.projectContext(ctx))
 	response, err := client.V1CloudAccountsAwsList(params)
 	if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	accounts := make([]*models.V1AwsAccount, len(response.Payload.Items))
@@ -136,38 +153,37 @@ func (h *V1Client) GetCloudAccountsAws() ([]*models.V1AwsAccount, error) {
 	return accounts, nil
 }
 
-func (h *V1Client) GetCloudConfigAws(configUID string) (*models.V1AwsCloudConfig, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetCloudConfigAws(ctx context.Context, configUID string) (*models.V1AwsCloudConfig, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1CloudConfigsAwsGetParamsWithContext(h.ctx).WithConfigUID(configUID)
+	params := clusterC.NewV1CloudConfigsAwsGetParamsWithContext(h.projectContext(ctx)).WithConfigUID(configUID)
 	success, err := client.V1CloudConfigsAwsGet(params)
-	if e, ok := err.(*hapitransport.TransportError); ok && e.HttpCode == 404 {
-		// TODO(saamalik) check with team if this is proper?
+	if herr.IsNotFound(err) {
 		return nil, nil
 	} else if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return success.Payload, nil
 }
 
-func (h *V1Client) ImportClusterAws(meta *models.V1ObjectMetaInputEntity) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) ImportClusterAws(ctx context.Context, meta *models.V1ObjectMetaInputEntity) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := clusterC.NewV1SpectroClustersAwsImportParamsWithContext(h.ctx).WithBody(
+	params := clusterC.NewV1SpectroClustersAwsImportParamsWithContext(h.projectContext(ctx)).WithBody(
 		&models.V1SpectroAwsClusterImportEntity{
 			Metadata: meta,
 		},
 	)
 	success, err := client.V1SpectroClustersAwsImport(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
@@ -1,131 +1,148 @@
 package client
 
 import (
-	hapitransport "github.com/spectrocloud/hapi/apiutil/transport"
+	"context"
+
+	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client/herr"
+
 	"github.com/spectrocloud/hapi/models"
 
 	clusterC "github.com/spectrocloud/hapi/spectrocluster/client/v1"
 )
 
-func (h *V1Client) CreateClusterAzure(cluster *models.V1SpectroAzureClusterEntity) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateClusterAzure(ctx context.Context, cluster *models.V1SpectroAzureClusterEntity) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := clusterC.NewV1SpectroClustersAzureCreateParamsWithContext(h.ctx).WithBody(cluster)
+	params := clusterC.NewV1SpectroClustersAzureCreateParamsWithContext(h.projectContext(ctx)).WithBody(cluster)
 	success, err := client.V1SpectroClustersAzureCreate(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
 }
 
-func (h *V1Client) CreateMachinePoolAzure(cloudConfigId string, machinePool *models.V1AzureMachinePoolConfigEntity) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateMachinePoolAzure(ctx context.Context, cloudConfigId string, machinePool *models.V1AzureMachinePoolConfigEntity) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsAzureMachinePoolCreateParamsWithContext(h.ctx).WithConfigUID(cloudConfigId).WithBody(machinePool)
+	params := clusterC.NewV1CloudConfigsAzureMachinePoolCreateParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithBody(machinePool)
 	_, err = client.V1CloudConfigsAzureMachinePoolCreate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) UpdateMachinePoolAzure(cloudConfigId string, machinePool *models.V1AzureMachinePoolConfigEntity) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) UpdateMachinePoolAzure(ctx context.Context, cloudConfigId string, machinePool *models.V1AzureMachinePoolConfigEntity) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsAzureMachinePoolUpdateParamsWithContext(h.ctx).
+	params := clusterC.NewV1CloudConfigsAzureMachinePoolUpdateParamsWithContext(h.projectContext(ctx)).
 		WithConfigUID(cloudConfigId).
 		WithMachinePoolName(*machinePool.PoolConfig.Name).
 		WithBody(machinePool)
 	_, err = client.V1CloudConfigsAzureMachinePoolUpdate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) DeleteMachinePoolAzure(cloudConfigId string, machinePoolName string) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) DeleteMachinePoolAzure(ctx context.Context, cloudConfigId string, machinePoolName string) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsAzureMachinePoolDeleteParamsWithContext(h.ctx).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
+	params := clusterC.NewV1CloudConfigsAzureMachinePoolDeleteParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
 	_, err = client.V1CloudConfigsAzureMachinePoolDelete(params)
-	return err
+	return herr.Wrap(err)
+}
+
+func (h *V1Client) GetMachinePoolMachinesAzure(ctx context.Context, cloudConfigId string, machinePoolName string) ([]*models.V1AzureMachine, error) {
+	client, err := h.getClusterClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := clusterC.NewV1CloudConfigsAzurePoolMachinesListParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
+	success, err := client.V1CloudConfigsAzurePoolMachinesList(params)
+	if err != nil {
+		return nil, herr.Wrap(err)
+	}
+
+	return success.Payload.Items, nil
 }
 
 // Cloud Account
 
-func (h *V1Client) CreateCloudAccountAzure(account *models.V1AzureAccount) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateCloudAccountAzure(ctx context.Context, account *models.V1AzureAccount) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := clusterC.NewV1CloudAccountsAzureCreateParamsWithContext(h.ctx).WithBody(account)
+	params := clusterC.NewV1CloudAccountsAzureCreateParamsWithContext(h.projectContext(ctx)).WithBody(account)
 	success, err := client.V1CloudAccountsAzureCreate(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
 }
 
-func (h *V1Client) UpdateCloudAccountAzure(account *models.V1AzureAccount) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) UpdateCloudAccountAzure(ctx context.Context, account *models.V1AzureAccount) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
 	uid := account.Metadata.UID
-	params := clusterC.NewV1CloudAccountsAzureUpdateParamsWithContext(h.ctx).WithUID(uid).WithBody(account)
+	params := clusterC.NewV1CloudAccountsAzureUpdateParamsWithContext(h.projectContext(ctx)).WithUID(uid).WithBody(account)
 	_, err = client.V1CloudAccountsAzureUpdate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) DeleteCloudAccountAzure(uid string) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) DeleteCloudAccountAzure(ctx context.Context, uid string) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudAccountsAzureDeleteParamsWithContext(h.ctx).WithUID(uid)
+	params := clusterC.NewV1CloudAccountsAzureDeleteParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	_, err = client.V1CloudAccountsAzureDelete(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) GetCloudAccountAzure(uid string) (*models.V1AzureAccount, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetCloudAccountAzure(ctx context.Context, uid string) (*models.V1AzureAccount, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1CloudAccountsAzureGetParamsWithContext(h.ctx).WithUID(uid)
+	params := clusterC.NewV1CloudAccountsAzureGetParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	success, err := client.V1CloudAccountsAzureGet(params)
-	if e, ok := err.(*hapitransport.TransportError); ok && e.HttpCode == 404 {
-		// TODO(saamalik) check with team if this is proper?
+	if herr.IsNotFound(err) {
 		return nil, nil
 	} else if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return success.Payload, nil
 }
 
-func (h *V1Client) GetCloudAccountsAzure() ([]*models.V1AzureAccount, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetCloudAccountsAzure(ctx context.Context) ([]*models.V1AzureAccount, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1CloudAccountsAzureListParamsWithContext(h.ctx)
+	params := clusterC.NewV1CloudAccountsAzureListParamsWithContext(h.projectContext(ctx))
 	response, err := client.V1CloudAccountsAzureList(params)
 	if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	accounts := make([]*models.V1AzureAccount, len(response.Payload.Items))
@@ -136,38 +153,37 @@ func (h *V1Client) GetCloudAccountsAzure() ([]*models.V1AzureAccount, error) {
 	return accounts, nil
 }
 
-func (h *V1Client) GetCloudConfigAzure(configUID string) (*models.V1AzureCloudConfig, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetCloudConfigAzure(ctx context.Context, configUID string) (*models.V1AzureCloudConfig, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1CloudConfigsAzureGetParamsWithContext(h.ctx).WithConfigUID(configUID)
+	params := clusterC.NewV1CloudConfigsAzureGetParamsWithContext(h.projectContext(ctx)).WithConfigUID(configUID)
 	success, err := client.V1CloudConfigsAzureGet(params)
-	if e, ok := err.(*hapitransport.TransportError); ok && e.HttpCode == 404 {
-		// TODO(saamalik) check with team if this is proper?
+	if herr.IsNotFound(err) {
 		return nil, nil
 	} else if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return success.Payload, nil
 }
 
-func (h *V1Client) ImportClusterAzure(meta *models.V1ObjectMetaInputEntity) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) ImportClusterAzure(ctx context.Context, meta *models.V1ObjectMetaInputEntity) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := clusterC.NewV1SpectroClustersAzureImportParamsWithContext(h.ctx).WithBody(
+	params := clusterC.NewV1SpectroClustersAzureImportParamsWithContext(h.projectContext(ctx)).WithBody(
 		&models.V1SpectroAzureClusterImportEntity{
 			Metadata: meta,
 		},
 	)
 	success, err := client.V1SpectroClustersAzureImport(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
@@ -1,88 +1,105 @@
 package client
 
 import (
-	hapitransport "github.com/spectrocloud/hapi/apiutil/transport"
+	"context"
+
+	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client/herr"
+
 	"github.com/spectrocloud/hapi/models"
 
 	clusterC "github.com/spectrocloud/hapi/spectrocluster/client/v1"
 )
 
-func (h *V1Client) CreateClusterEks(cluster *models.V1SpectroEksClusterEntity) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateClusterEks(ctx context.Context, cluster *models.V1SpectroEksClusterEntity) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := clusterC.NewV1SpectroClustersEksCreateParamsWithContext(h.ctx).WithBody(cluster)
+	params := clusterC.NewV1SpectroClustersEksCreateParamsWithContext(h.projectContext(ctx)).WithBody(cluster)
 	success, err := client.V1SpectroClustersEksCreate(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
 }
 
-func (h *V1Client) CreateMachinePoolEks(cloudConfigId string, machinePool *models.V1EksMachinePoolConfigEntity) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateMachinePoolEks(ctx context.Context, cloudConfigId string, machinePool *models.V1EksMachinePoolConfigEntity) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsEksMachinePoolCreateParamsWithContext(h.ctx).WithConfigUID(cloudConfigId).WithBody(machinePool)
+	params := clusterC.NewV1CloudConfigsEksMachinePoolCreateParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithBody(machinePool)
 	_, err = client.V1CloudConfigsEksMachinePoolCreate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) UpdateMachinePoolEks(cloudConfigId string, machinePool *models.V1EksMachinePoolConfigEntity) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) UpdateMachinePoolEks(ctx context.Context, cloudConfigId string, machinePool *models.V1EksMachinePoolConfigEntity) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsEksMachinePoolUpdateParamsWithContext(h.ctx).
+	params := clusterC.NewV1CloudConfigsEksMachinePoolUpdateParamsWithContext(h.projectContext(ctx)).
 		WithConfigUID(cloudConfigId).
 		WithMachinePoolName(*machinePool.PoolConfig.Name).
 		WithBody(machinePool)
 	_, err = client.V1CloudConfigsEksMachinePoolUpdate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) DeleteMachinePoolEks(cloudConfigId string, machinePoolName string) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) DeleteMachinePoolEks(ctx context.Context, cloudConfigId string, machinePoolName string) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsEksMachinePoolDeleteParamsWithContext(h.ctx).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
+	params := clusterC.NewV1CloudConfigsEksMachinePoolDeleteParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
 	_, err = client.V1CloudConfigsEksMachinePoolDelete(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) UpdateFargateProfiles(cloudConfigId string, fargateProfiles *models.V1EksFargateProfiles) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetMachinePoolMachinesEks(ctx context.Context, cloudConfigId string, machinePoolName string) ([]*models.V1AwsMachine, error) {
+	client, err := h.getClusterClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := clusterC.NewV1CloudConfigsEksPoolMachinesListParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
+	success, err := client.V1CloudConfigsEksPoolMachinesList(params)
 	if err != nil {
-		return nil
+		return nil, herr.Wrap(err)
 	}
-	params := clusterC.NewV1CloudConfigsEksUIDFargateProfilesUpdateParamsWithContext(h.ctx).
+
+	return success.Payload.Items, nil
+}
+
+func (h *V1Client) UpdateFargateProfiles(ctx context.Context, cloudConfigId string, fargateProfiles *models.V1EksFargateProfiles) error {
+	client, err := h.getClusterClient(ctx)
+	if err != nil {
+		return err
+	}
+	params := clusterC.NewV1CloudConfigsEksUIDFargateProfilesUpdateParamsWithContext(h.projectContext(ctx)).
 		WithConfigUID(cloudConfigId).
 		WithBody(fargateProfiles)
 	_, err = client.V1CloudConfigsEksUIDFargateProfilesUpdate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) GetCloudConfigEks(configUID string) (*models.V1EksCloudConfig, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetCloudConfigEks(ctx context.Context, configUID string) (*models.V1EksCloudConfig, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1CloudConfigsEksGetParamsWithContext(h.ctx).WithConfigUID(configUID)
+	params := clusterC.NewV1CloudConfigsEksGetParamsWithContext(h.projectContext(ctx)).WithConfigUID(configUID)
 	success, err := client.V1CloudConfigsEksGet(params)
-	if e, ok := err.(*hapitransport.TransportError); ok && e.HttpCode == 404 {
-		// TODO(saamalik) check with team if this is proper?
+	if herr.IsNotFound(err) {
 		return nil, nil
 	} else if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return success.Payload, nil
@@ -1,131 +1,148 @@
 package client
 
 import (
-	hapitransport "github.com/spectrocloud/hapi/apiutil/transport"
+	"context"
+
+	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client/herr"
+
 	"github.com/spectrocloud/hapi/models"
 
 	clusterC "github.com/spectrocloud/hapi/spectrocluster/client/v1"
 )
 
-func (h *V1Client) CreateClusterGcp(cluster *models.V1SpectroGcpClusterEntity) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateClusterGcp(ctx context.Context, cluster *models.V1SpectroGcpClusterEntity) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := clusterC.NewV1SpectroClustersGcpCreateParamsWithContext(h.ctx).WithBody(cluster)
+	params := clusterC.NewV1SpectroClustersGcpCreateParamsWithContext(h.projectContext(ctx)).WithBody(cluster)
 	success, err := client.V1SpectroClustersGcpCreate(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
 }
 
-func (h *V1Client) CreateMachinePoolGcp(cloudConfigId string, machinePool *models.V1GcpMachinePoolConfigEntity) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateMachinePoolGcp(ctx context.Context, cloudConfigId string, machinePool *models.V1GcpMachinePoolConfigEntity) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsGcpMachinePoolCreateParamsWithContext(h.ctx).WithConfigUID(cloudConfigId).WithBody(machinePool)
+	params := clusterC.NewV1CloudConfigsGcpMachinePoolCreateParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithBody(machinePool)
 	_, err = client.V1CloudConfigsGcpMachinePoolCreate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) UpdateMachinePoolGcp(cloudConfigId string, machinePool *models.V1GcpMachinePoolConfigEntity) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) UpdateMachinePoolGcp(ctx context.Context, cloudConfigId string, machinePool *models.V1GcpMachinePoolConfigEntity) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsGcpMachinePoolUpdateParamsWithContext(h.ctx).
+	params := clusterC.NewV1CloudConfigsGcpMachinePoolUpdateParamsWithContext(h.projectContext(ctx)).
 		WithConfigUID(cloudConfigId).
 		WithMachinePoolName(*machinePool.PoolConfig.Name).
 		WithBody(machinePool)
 	_, err = client.V1CloudConfigsGcpMachinePoolUpdate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) DeleteMachinePoolGcp(cloudConfigId string, machinePoolName string) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) DeleteMachinePoolGcp(ctx context.Context, cloudConfigId string, machinePoolName string) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsGcpMachinePoolDeleteParamsWithContext(h.ctx).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
+	params := clusterC.NewV1CloudConfigsGcpMachinePoolDeleteParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
 	_, err = client.V1CloudConfigsGcpMachinePoolDelete(params)
-	return err
+	return herr.Wrap(err)
+}
+
+func (h *V1Client) GetMachinePoolMachinesGcp(ctx context.Context, cloudConfigId string, machinePoolName string) ([]*models.V1GcpMachine, error) {
+	client, err := h.getClusterClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := clusterC.NewV1CloudConfigsGcpPoolMachinesListParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
+	success, err := client.V1CloudConfigsGcpPoolMachinesList(params)
+	if err != nil {
+		return nil, herr.Wrap(err)
+	}
+
+	return success.Payload.Items, nil
 }
 
 // Cloud Account
 
-func (h *V1Client) CreateCloudAccountGcp(account *models.V1GcpAccountEntity) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateCloudAccountGcp(ctx context.Context, account *models.V1GcpAccountEntity) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := clusterC.NewV1CloudAccountsGcpCreateParamsWithContext(h.ctx).WithBody(account)
+	params := clusterC.NewV1CloudAccountsGcpCreateParamsWithContext(h.projectContext(ctx)).WithBody(account)
 	success, err := client.V1CloudAccountsGcpCreate(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
 }
 
-func (h *V1Client) UpdateCloudAccountGcp(account *models.V1GcpAccountEntity) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) UpdateCloudAccountGcp(ctx context.Context, account *models.V1GcpAccountEntity) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
 	uid := account.Metadata.UID
-	params := clusterC.NewV1CloudAccountsGcpUpdateParamsWithContext(h.ctx).WithUID(uid).WithBody(account)
+	params := clusterC.NewV1CloudAccountsGcpUpdateParamsWithContext(h.projectContext(ctx)).WithUID(uid).WithBody(account)
 	_, err = client.V1CloudAccountsGcpUpdate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) DeleteCloudAccountGcp(uid string) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) DeleteCloudAccountGcp(ctx context.Context, uid string) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudAccountsGcpDeleteParamsWithContext(h.ctx).WithUID(uid)
+	params := clusterC.NewV1CloudAccountsGcpDeleteParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	_, err = client.V1CloudAccountsGcpDelete(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) GetCloudAccountGcp(uid string) (*models.V1GcpAccount, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetCloudAccountGcp(ctx context.Context, uid string) (*models.V1GcpAccount, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1CloudAccountsGcpGetParamsWithContext(h.ctx).WithUID(uid)
+	params := clusterC.NewV1CloudAccountsGcpGetParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	success, err := client.V1CloudAccountsGcpGet(params)
-	if e, ok := err.(*hapitransport.TransportError); ok && e.HttpCode == 404 {
-		// TODO(saamalik) check with team if this is proper?
+	if herr.IsNotFound(err) {
 		return nil, nil
 	} else if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return success.Payload, nil
 }
 
-func (h *V1Client) GetCloudAccountsGcp() ([]*models.V1GcpAccount, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetCloudAccountsGcp(ctx context.Context) ([]*models.V1GcpAccount, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1CloudAccountsGcpListParamsWithContext(h.ctx)
+	params := clusterC.NewV1CloudAccountsGcpListParamsWithContext(h.projectContext(ctx))
 	response, err := client.V1CloudAccountsGcpList(params)
 	if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	accounts := make([]*models.V1GcpAccount, len(response.Payload.Items))
@@ -136,38 +153,37 @@ func (h *V1Client) GetCloudAccountsGcp() ([]*models.V1GcpAccount, error) {
 	return accounts, nil
 }
 
-func (h *V1Client) GetCloudConfigGcp(configUID string) (*models.V1GcpCloudConfig, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetCloudConfigGcp(ctx context.Context, configUID string) (*models.V1GcpCloudConfig, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1CloudConfigsGcpGetParamsWithContext(h.ctx).WithConfigUID(configUID)
+	params := clusterC.NewV1CloudConfigsGcpGetParamsWithContext(h.projectContext(ctx)).WithConfigUID(configUID)
 	success, err := client.V1CloudConfigsGcpGet(params)
-	if e, ok := err.(*hapitransport.TransportError); ok && e.HttpCode == 404 {
-		// TODO(saamalik) check with team if this is proper?
+	if herr.IsNotFound(err) {
 		return nil, nil
 	} else if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return success.Payload, nil
 }
 
-func (h *V1Client) ImportClusterGcp(meta *models.V1ObjectMetaInputEntity) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) ImportClusterGcp(ctx context.Context, meta *models.V1ObjectMetaInputEntity) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := clusterC.NewV1SpectroClustersGcpImportParamsWithContext(h.ctx).WithBody(
+	params := clusterC.NewV1SpectroClustersGcpImportParamsWithContext(h.projectContext(ctx)).WithBody(
 		&models.V1SpectroGcpClusterImportEntity{
 			Metadata: meta,
 		},
 	)
 	success, err := client.V1SpectroClustersGcpImport(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
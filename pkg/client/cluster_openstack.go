@@ -1,148 +1,163 @@
 package client
 
 import (
-	hapitransport "github.com/spectrocloud/hapi/apiutil/transport"
+	"context"
+
 	"github.com/spectrocloud/hapi/models"
 	clusterC "github.com/spectrocloud/hapi/spectrocluster/client/v1"
 	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client/herr"
 )
 
-func (h *V1Client) CreateClusterOpenStack(cluster *models.V1SpectroOpenStackClusterEntity) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateClusterOpenStack(ctx context.Context, cluster *models.V1SpectroOpenStackClusterEntity) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := clusterC.NewV1SpectroClustersOpenStackCreateParamsWithContext(h.ctx).WithBody(cluster)
+	params := clusterC.NewV1SpectroClustersOpenStackCreateParamsWithContext(h.projectContext(ctx)).WithBody(cluster)
 	success, err := client.V1SpectroClustersOpenStackCreate(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
 }
 
-func (h *V1Client) CreateCloudAccountOpenStack(account *models.V1OpenStackAccount) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateCloudAccountOpenStack(ctx context.Context, account *models.V1OpenStackAccount) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := clusterC.NewV1CloudAccountsOpenStackCreateParamsWithContext(h.ctx).WithBody(account)
+	params := clusterC.NewV1CloudAccountsOpenStackCreateParamsWithContext(h.projectContext(ctx)).WithBody(account)
 	success, err := client.V1CloudAccountsOpenStackCreate(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
 }
 
-func (h *V1Client) CreateMachinePoolOpenStack(cloudConfigId string, machinePool *models.V1OpenStackMachinePoolConfigEntity) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateMachinePoolOpenStack(ctx context.Context, cloudConfigId string, machinePool *models.V1OpenStackMachinePoolConfigEntity) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsOpenStackMachinePoolCreateParamsWithContext(h.ctx).WithConfigUID(cloudConfigId).WithBody(machinePool)
+	params := clusterC.NewV1CloudConfigsOpenStackMachinePoolCreateParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithBody(machinePool)
 	_, err = client.V1CloudConfigsOpenStackMachinePoolCreate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) UpdateMachinePoolOpenStack(cloudConfigId string, machinePool *models.V1OpenStackMachinePoolConfigEntity) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) UpdateMachinePoolOpenStack(ctx context.Context, cloudConfigId string, machinePool *models.V1OpenStackMachinePoolConfigEntity) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsOpenStackMachinePoolUpdateParamsWithContext(h.ctx).
+	params := clusterC.NewV1CloudConfigsOpenStackMachinePoolUpdateParamsWithContext(h.projectContext(ctx)).
 		WithConfigUID(cloudConfigId).
 		WithMachinePoolName(*machinePool.PoolConfig.Name).
 		WithBody(machinePool)
 	_, err = client.V1CloudConfigsOpenStackMachinePoolUpdate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) DeleteMachinePoolOpenStack(cloudConfigId string, machinePoolName string) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) DeleteMachinePoolOpenStack(ctx context.Context, cloudConfigId string, machinePoolName string) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsOpenStackMachinePoolDeleteParamsWithContext(h.ctx).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
+	params := clusterC.NewV1CloudConfigsOpenStackMachinePoolDeleteParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
 	_, err = client.V1CloudConfigsOpenStackMachinePoolDelete(params)
-	return err
+	return herr.Wrap(err)
+}
+
+func (h *V1Client) GetMachinePoolMachinesOpenStack(ctx context.Context, cloudConfigId string, machinePoolName string) ([]*models.V1OpenStackMachine, error) {
+	client, err := h.getClusterClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := clusterC.NewV1CloudConfigsOpenStackPoolMachinesListParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
+	success, err := client.V1CloudConfigsOpenStackPoolMachinesList(params)
+	if err != nil {
+		return nil, herr.Wrap(err)
+	}
+
+	return success.Payload.Items, nil
 }
 
-func (h *V1Client) GetCloudAccountOpenStack(uid string) (*models.V1OpenStackAccount, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetCloudAccountOpenStack(ctx context.Context, uid string) (*models.V1OpenStackAccount, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1CloudAccountsOpenStackGetParamsWithContext(h.ctx).WithUID(uid)
+	params := clusterC.NewV1CloudAccountsOpenStackGetParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	success, err := client.V1CloudAccountsOpenStackGet(params)
-	if e, ok := err.(*hapitransport.TransportError); ok && e.HttpCode == 404 {
-		// TODO(saamalik) check with team if this is proper?
+	if herr.IsNotFound(err) {
 		return nil, nil
 	} else if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return success.Payload, nil
 }
 
-func (h *V1Client) GetCloudConfigOpenStack(configUID string) (*models.V1OpenStackCloudConfig, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetCloudConfigOpenStack(ctx context.Context, configUID string) (*models.V1OpenStackCloudConfig, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1CloudConfigsOpenStackGetParamsWithContext(h.ctx).WithConfigUID(configUID)
+	params := clusterC.NewV1CloudConfigsOpenStackGetParamsWithContext(h.projectContext(ctx)).WithConfigUID(configUID)
 	success, err := client.V1CloudConfigsOpenStackGet(params)
 
 	if herr.IsNotFound(err) {
 		return nil, nil
 	} else if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return success.Payload, nil
 }
 
 
-func (h *V1Client) UpdateCloudAccountOpenStack(account *models.V1OpenStackAccount) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) UpdateCloudAccountOpenStack(ctx context.Context, account *models.V1OpenStackAccount) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
 	uid := account.Metadata.UID
-	params := clusterC.NewV1CloudAccountsOpenStackUpdateParamsWithContext(h.ctx).WithUID(uid).WithBody(account)
+	params := clusterC.NewV1CloudAccountsOpenStackUpdateParamsWithContext(h.projectContext(ctx)).WithUID(uid).WithBody(account)
 	_, err = client.V1CloudAccountsOpenStackUpdate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) DeleteCloudAccountOpenStack(uid string) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) DeleteCloudAccountOpenStack(ctx context.Context, uid string) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudAccountsOpenStackDeleteParamsWithContext(h.ctx).WithUID(uid)
+	params := clusterC.NewV1CloudAccountsOpenStackDeleteParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	_, err = client.V1CloudAccountsOpenStackDelete(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) GetCloudAccountsOpenStack() ([]*models.V1OpenStackAccount, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetCloudAccountsOpenStack(ctx context.Context) ([]*models.V1OpenStackAccount, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1CloudAccountsOpenStackListParamsWithContext(h.ctx)
+	params := clusterC.NewV1CloudAccountsOpenStackListParamsWithContext(h.projectContext(ctx))
 	response, err := client.V1CloudAccountsOpenStackList(params)
 	if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	accounts := make([]*models.V1OpenStackAccount, len(response.Payload.Items))
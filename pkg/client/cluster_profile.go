@@ -1,75 +1,76 @@
 package client
 
 import (
+	"context"
+
+	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client/herr"
+
 	"strings"
 
 	"github.com/spectrocloud/gomi/pkg/ptr"
-	hapitransport "github.com/spectrocloud/hapi/apiutil/transport"
 	"github.com/spectrocloud/hapi/models"
 
 	clusterC "github.com/spectrocloud/hapi/spectrocluster/client/v1"
 )
 
-func (h *V1Client) DeleteClusterProfile(uid string) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) DeleteClusterProfile(ctx context.Context, uid string) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1ClusterProfilesDeleteParamsWithContext(h.ctx).WithUID(uid)
+	params := clusterC.NewV1ClusterProfilesDeleteParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	_, err = client.V1ClusterProfilesDelete(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) GetClusterProfile(uid string) (*models.V1ClusterProfile, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetClusterProfile(ctx context.Context, uid string) (*models.V1ClusterProfile, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1ClusterProfilesGetParamsWithContext(h.ctx).WithUID(uid)
+	params := clusterC.NewV1ClusterProfilesGetParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	success, err := client.V1ClusterProfilesGet(params)
-	if e, ok := err.(*hapitransport.TransportError); ok && e.HttpCode == 404 {
-		// TODO(saamalik) check with team if this is proper?
+	if herr.IsNotFound(err) {
 		return nil, nil
 	} else if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return success.Payload, nil
 }
 
-func (h *V1Client) GetClusterProfileManifestPack(clusterProfileUID, packUID string) ([]*models.V1ManifestEntity, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetClusterProfileManifestPack(ctx context.Context, clusterProfileUID, packUID string) ([]*models.V1ManifestEntity, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	//params := clusterC.NewV1ClusterProfilesGetParamsWithContext(h.ctx).WithUID(uid)
-	params := clusterC.NewV1ClusterProfilesUIDPacksUIDManifestsParamsWithContext(h.ctx).
+	//params := clusterC.NewV1ClusterProfilesGetParamsWithContext(h.projectContext(ctx)).WithUID(uid)
+	params := clusterC.NewV1ClusterProfilesUIDPacksUIDManifestsParamsWithContext(h.projectContext(ctx)).
 		WithUID(clusterProfileUID).WithPackUID(packUID)
 	success, err := client.V1ClusterProfilesUIDPacksUIDManifests(params)
-	if e, ok := err.(*hapitransport.TransportError); ok && e.HttpCode == 404 {
-		// TODO(saamalik) check with team if this is proper?
+	if herr.IsNotFound(err) {
 		return nil, nil
 	} else if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return success.Payload.Items, nil
 }
 
-func (h *V1Client) GetClusterProfiles() ([]*models.V1ClusterProfile, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetClusterProfiles(ctx context.Context) ([]*models.V1ClusterProfile, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	limit := int64(0)
-	params := clusterC.NewV1ClusterProfilesListParamsWithContext(h.ctx).WithLimit(&limit)
+	params := clusterC.NewV1ClusterProfilesListParamsWithContext(h.projectContext(ctx)).WithLimit(&limit)
 	response, err := client.V1ClusterProfilesList(params)
 	if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	profiles := make([]*models.V1ClusterProfile, len(response.Payload.Items))
@@ -80,13 +81,13 @@ func (h *V1Client) GetClusterProfiles() ([]*models.V1ClusterProfile, error) {
 	return profiles, nil
 }
 
-func (h *V1Client) GetPacks(filters []string) ([]*models.V1PackSummary, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetPacks(ctx context.Context, filters []string) ([]*models.V1PackSummary, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1PacksSummaryListParamsWithContext(h.ctx)
+	params := clusterC.NewV1PacksSummaryListParamsWithContext(h.projectContext(ctx))
 	if filters != nil {
 		filterString := ptr.StringPtr(strings.Join(filters, "AND"))
 		params = params.WithFilters(filterString)
@@ -94,7 +95,7 @@ func (h *V1Client) GetPacks(filters []string) ([]*models.V1PackSummary, error) {
 
 	response, err := client.V1PacksSummaryList(params)
 	if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	packs := make([]*models.V1PackSummary, len(response.Payload.Items))
@@ -105,43 +106,43 @@ func (h *V1Client) GetPacks(filters []string) ([]*models.V1PackSummary, error) {
 	return packs, nil
 }
 
-func (h *V1Client) UpdateClusterProfile(clusterProfile *models.V1ClusterProfileUpdateEntity) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) UpdateClusterProfile(ctx context.Context, clusterProfile *models.V1ClusterProfileUpdateEntity) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
 	uid := clusterProfile.Metadata.UID
-	params := clusterC.NewV1ClusterProfilesUpdateParamsWithContext(h.ctx).WithUID(uid).WithBody(clusterProfile)
+	params := clusterC.NewV1ClusterProfilesUpdateParamsWithContext(h.projectContext(ctx)).WithUID(uid).WithBody(clusterProfile)
 	_, err = client.V1ClusterProfilesUpdate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) CreateClusterProfile(clusterProfile *models.V1ClusterProfileEntity) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateClusterProfile(ctx context.Context, clusterProfile *models.V1ClusterProfileEntity) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := clusterC.NewV1ClusterProfilesCreateParamsWithContext(h.ctx).WithBody(clusterProfile)
+	params := clusterC.NewV1ClusterProfilesCreateParamsWithContext(h.projectContext(ctx)).WithBody(clusterProfile)
 	success, err := client.V1ClusterProfilesCreate(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
 }
 
-func (h *V1Client) PublishClusterProfile(uid string) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) PublishClusterProfile(ctx context.Context, uid string) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	params := clusterC.NewV1ClusterProfilesPublishParamsWithContext(h.ctx).WithUID(uid)
+	params := clusterC.NewV1ClusterProfilesPublishParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	_, err = client.V1ClusterProfilesPublish(params)
 	if err != nil {
-		return err
+		return herr.Wrap(err)
 	}
 
 	return nil
@@ -1,131 +1,148 @@
 package client
 
 import (
-	hapitransport "github.com/spectrocloud/hapi/apiutil/transport"
+	"context"
+
+	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client/herr"
+
 	"github.com/spectrocloud/hapi/models"
 
 	clusterC "github.com/spectrocloud/hapi/spectrocluster/client/v1"
 )
 
-func (h *V1Client) CreateClusterVsphere(cluster *models.V1SpectroVsphereClusterEntity) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateClusterVsphere(ctx context.Context, cluster *models.V1SpectroVsphereClusterEntity) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := clusterC.NewV1SpectroClustersVsphereCreateParamsWithContext(h.ctx).WithBody(cluster)
+	params := clusterC.NewV1SpectroClustersVsphereCreateParamsWithContext(h.projectContext(ctx)).WithBody(cluster)
 	success, err := client.V1SpectroClustersVsphereCreate(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
 }
 
-func (h *V1Client) CreateMachinePoolVsphere(cloudConfigId string, machinePool *models.V1VsphereMachinePoolConfigEntity) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateMachinePoolVsphere(ctx context.Context, cloudConfigId string, machinePool *models.V1VsphereMachinePoolConfigEntity) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsVsphereMachinePoolCreateParamsWithContext(h.ctx).WithConfigUID(cloudConfigId).WithBody(machinePool)
+	params := clusterC.NewV1CloudConfigsVsphereMachinePoolCreateParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithBody(machinePool)
 	_, err = client.V1CloudConfigsVsphereMachinePoolCreate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) UpdateMachinePoolVsphere(cloudConfigId string, machinePool *models.V1VsphereMachinePoolConfigEntity) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) UpdateMachinePoolVsphere(ctx context.Context, cloudConfigId string, machinePool *models.V1VsphereMachinePoolConfigEntity) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsVsphereMachinePoolUpdateParamsWithContext(h.ctx).
+	params := clusterC.NewV1CloudConfigsVsphereMachinePoolUpdateParamsWithContext(h.projectContext(ctx)).
 		WithConfigUID(cloudConfigId).
 		WithMachinePoolName(*machinePool.PoolConfig.Name).
 		WithBody(machinePool)
 	_, err = client.V1CloudConfigsVsphereMachinePoolUpdate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) DeleteMachinePoolVsphere(cloudConfigId string, machinePoolName string) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) DeleteMachinePoolVsphere(ctx context.Context, cloudConfigId string, machinePoolName string) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudConfigsVsphereMachinePoolDeleteParamsWithContext(h.ctx).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
+	params := clusterC.NewV1CloudConfigsVsphereMachinePoolDeleteParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
 	_, err = client.V1CloudConfigsVsphereMachinePoolDelete(params)
-	return err
+	return herr.Wrap(err)
+}
+
+func (h *V1Client) GetMachinePoolMachinesVsphere(ctx context.Context, cloudConfigId string, machinePoolName string) ([]*models.V1VsphereMachine, error) {
+	client, err := h.getClusterClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := clusterC.NewV1CloudConfigsVspherePoolMachinesListParamsWithContext(h.projectContext(ctx)).WithConfigUID(cloudConfigId).WithMachinePoolName(machinePoolName)
+	success, err := client.V1CloudConfigsVspherePoolMachinesList(params)
+	if err != nil {
+		return nil, herr.Wrap(err)
+	}
+
+	return success.Payload.Items, nil
 }
 
 // Cloud Account
 
-func (h *V1Client) CreateCloudAccountVsphere(account *models.V1VsphereAccount) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateCloudAccountVsphere(ctx context.Context, account *models.V1VsphereAccount) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := clusterC.NewV1CloudAccountsVsphereCreateParamsWithContext(h.ctx).WithBody(account)
+	params := clusterC.NewV1CloudAccountsVsphereCreateParamsWithContext(h.projectContext(ctx)).WithBody(account)
 	success, err := client.V1CloudAccountsVsphereCreate(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
 }
 
-func (h *V1Client) UpdateCloudAccountVsphere(account *models.V1VsphereAccount) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) UpdateCloudAccountVsphere(ctx context.Context, account *models.V1VsphereAccount) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
 	uid := account.Metadata.UID
-	params := clusterC.NewV1CloudAccountsVsphereUpdateParamsWithContext(h.ctx).WithUID(uid).WithBody(account)
+	params := clusterC.NewV1CloudAccountsVsphereUpdateParamsWithContext(h.projectContext(ctx)).WithUID(uid).WithBody(account)
 	_, err = client.V1CloudAccountsVsphereUpdate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) DeleteCloudAccountVsphere(uid string) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) DeleteCloudAccountVsphere(ctx context.Context, uid string) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1CloudAccountsVsphereDeleteParamsWithContext(h.ctx).WithUID(uid)
+	params := clusterC.NewV1CloudAccountsVsphereDeleteParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	_, err = client.V1CloudAccountsVsphereDelete(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) GetCloudAccountVsphere(uid string) (*models.V1VsphereAccount, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetCloudAccountVsphere(ctx context.Context, uid string) (*models.V1VsphereAccount, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	getParams := clusterC.NewV1CloudAccountsVsphereGetParamsWithContext(h.ctx).WithUID(uid)
+	getParams := clusterC.NewV1CloudAccountsVsphereGetParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	success, err := client.V1CloudAccountsVsphereGet(getParams)
-	if e, ok := err.(*hapitransport.TransportError); ok && e.HttpCode == 404 {
-		// TODO(saamalik) check with team if this is proper?
+	if herr.IsNotFound(err) {
 		return nil, nil
 	} else if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return success.Payload, nil
 }
 
-func (h *V1Client) GetCloudAccountsVsphere() ([]*models.V1VsphereAccount, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetCloudAccountsVsphere(ctx context.Context) ([]*models.V1VsphereAccount, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1CloudAccountsVsphereListParamsWithContext(h.ctx)
+	params := clusterC.NewV1CloudAccountsVsphereListParamsWithContext(h.projectContext(ctx))
 	response, err := client.V1CloudAccountsVsphereList(params)
 	if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	accounts := make([]*models.V1VsphereAccount, len(response.Payload.Items))
@@ -136,38 +153,37 @@ func (h *V1Client) GetCloudAccountsVsphere() ([]*models.V1VsphereAccount, error)
 	return accounts, nil
 }
 
-func (h *V1Client) GetCloudConfigVsphere(configUID string) (*models.V1VsphereCloudConfig, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetCloudConfigVsphere(ctx context.Context, configUID string) (*models.V1VsphereCloudConfig, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1CloudConfigsVsphereGetParamsWithContext(h.ctx).WithConfigUID(configUID)
+	params := clusterC.NewV1CloudConfigsVsphereGetParamsWithContext(h.projectContext(ctx)).WithConfigUID(configUID)
 	success, err := client.V1CloudConfigsVsphereGet(params)
-	if e, ok := err.(*hapitransport.TransportError); ok && e.HttpCode == 404 {
-		// TODO(saamalik) check with team if this is proper?
+	if herr.IsNotFound(err) {
 		return nil, nil
 	} else if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return success.Payload, nil
 }
 
-func (h *V1Client) ImportClusterVsphere(meta *models.V1ObjectMetaInputEntity) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) ImportClusterVsphere(ctx context.Context, meta *models.V1ObjectMetaInputEntity) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := clusterC.NewV1SpectroClustersVsphereImportParamsWithContext(h.ctx).WithBody(
+	params := clusterC.NewV1SpectroClustersVsphereImportParamsWithContext(h.projectContext(ctx)).WithBody(
 		&models.V1SpectroVsphereClusterImportEntity{
 			Metadata: meta,
 		},
 	)
 	success, err := client.V1SpectroClustersVsphereImport(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
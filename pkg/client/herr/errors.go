@@ -1,11 +1,140 @@
 package herr
 
-import "github.com/spectrocloud/hapi/apiutil"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-openapi/runtime"
+	"github.com/spectrocloud/hapi/apiutil"
+	hapitransport "github.com/spectrocloud/hapi/apiutil/transport"
+)
+
+// Error is a failed Spectro Cloud API call with the detail needed to act on it
+type Error struct {
+	// HttpCode is the HTTP status of the response, 0 when unknown
+	HttpCode int
+	// Code is the API error code, eg ResourceNotFound
+	Code string
+	// Message is the API error message
+	Message string
+	// RequestID is the reference of the failed request, to be quoted to support
+	RequestID string
+
+	err error
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Message)
+	if b.Len() == 0 && e.err != nil {
+		b.WriteString(e.err.Error())
+	}
+
+	details := make([]string, 0, 3)
+	if e.HttpCode != 0 {
+		details = append(details, fmt.Sprintf("http %d", e.HttpCode))
+	}
+	if e.Code != "" {
+		details = append(details, fmt.Sprintf("code %s", e.Code))
+	}
+	if e.RequestID != "" {
+		details = append(details, fmt.Sprintf("request id %s", e.RequestID))
+	}
+	if len(details) > 0 {
+		b.WriteString(fmt.Sprintf(" (%s)", strings.Join(details, ", ")))
+	}
+	return b.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Wrap converts an error returned by the API into an *Error. Errors that did
+// not come from an API response, and nil, are returned unchanged.
+func Wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	httpCode := 0
+	var te *hapitransport.TransportError
+	var ae *runtime.APIError
+	if errors.As(err, &te) {
+		httpCode = te.HttpCode
+	} else if errors.As(err, &ae) {
+		httpCode = ae.Code
+	}
+
+	v1Err := apiutil.ToV1ErrorObj(err)
+	if httpCode == 0 && v1Err.Code == "" {
+		return err
+	}
+
+	return &Error{
+		HttpCode:  httpCode,
+		Code:      v1Err.Code,
+		Message:   v1Err.Message,
+		RequestID: v1Err.Ref,
+		err:       err,
+	}
+}
+
+func classify(err error) (int, string) {
+	if err == nil {
+		return 0, ""
+	}
+
+	var e *Error
+	if errors.As(Wrap(err), &e) {
+		return e.HttpCode, e.Code
+	}
+	return 0, ""
+}
 
 func IsNotFound(err error) bool {
-	return apiutil.ToV1ErrorObj(err).Code == "ResourceNotFound"
+	httpCode, code := classify(err)
+	return httpCode == http.StatusNotFound || code == "ResourceNotFound"
+}
+
+func IsConflict(err error) bool {
+	httpCode, code := classify(err)
+	return httpCode == http.StatusConflict || code == "ResourceConflict"
+}
+
+func IsUnauthorized(err error) bool {
+	httpCode, _ := classify(err)
+	return httpCode == http.StatusUnauthorized
+}
+
+func IsForbidden(err error) bool {
+	httpCode, _ := classify(err)
+	return httpCode == http.StatusForbidden
+}
+
+func IsRateLimited(err error) bool {
+	httpCode, _ := classify(err)
+	return httpCode == http.StatusTooManyRequests
+}
+
+func IsValidation(err error) bool {
+	httpCode, _ := classify(err)
+	return httpCode == http.StatusBadRequest || httpCode == http.StatusUnprocessableEntity
+}
+
+func IsServerError(err error) bool {
+	httpCode, _ := classify(err)
+	return httpCode >= http.StatusInternalServerError
 }
 
 func IsBackupNotConfigured(err error) bool {
-	return apiutil.ToV1ErrorObj(err).Code == "BackupNotConfigured"
+	_, code := classify(err)
+	return code == "BackupNotConfigured"
 }
@@ -0,0 +1,206 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
+)
+
+const redacted = "<redacted>"
+
+// sensitiveHeaders are never written to the log or the trace
+var sensitiveHeaders = []string{"Authorization", "ApiKey", "Cookie", "Set-Cookie"}
+
+// sensitiveKeys match, case insensitively, the JSON keys whose values are
+// redacted: passwords, cloud secrets, tokens and kubeconfigs
+var sensitiveKeys = []string{"password", "secret", "token", "credential", "kubeconfig", "apikey", "authorization", "privatekey"}
+
+// httpTrace serialises the JSONL entries written by every client sharing it.
+// The file is opened for each entry, so nothing is left unflushed or open when
+// the provider exits.
+type httpTrace struct {
+	lock sync.Mutex
+	path string
+}
+
+type httpTraceEntry struct {
+	Time            time.Time   `json:"time"`
+	Method          string      `json:"method"`
+	URL             string      `json:"url"`
+	RequestHeaders  http.Header `json:"request_headers,omitempty"`
+	RequestBody     string      `json:"request_body,omitempty"`
+	Status          int         `json:"status,omitempty"`
+	ResponseHeaders http.Header `json:"response_headers,omitempty"`
+	ResponseBody    string      `json:"response_body,omitempty"`
+	DurationMs      int64       `json:"duration_ms"`
+	Error           string      `json:"error,omitempty"`
+}
+
+func (t *httpTrace) write(entry *httpTraceEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	f, err := os.OpenFile(t.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Printf("[WARN] Failed to open HTTP trace: %v", err)
+		return
+	}
+	_, err = f.Write(append(line, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		log.Printf("[WARN] Failed to write HTTP trace: %v", err)
+	}
+}
+
+// logTransport logs every request and response at DEBUG and, when a trace is
+// configured, writes them to it. Secrets are redacted from both.
+type logTransport struct {
+	next  http.RoundTripper
+	trace *httpTrace
+}
+
+func newLogTransport(next http.RoundTripper, trace *httpTrace) http.RoundTripper {
+	if trace == nil && !logging.IsDebugOrHigher() {
+		return next
+	}
+	return &logTransport{next: next, trace: trace}
+}
+
+func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	entry := &httpTraceEntry{
+		Time:           time.Now().UTC(),
+		Method:         req.Method,
+		URL:            req.URL.String(),
+		RequestHeaders: redactHeaders(req.Header),
+	}
+
+	if req.Body != nil {
+		body, err := readRequestBody(req)
+		if err != nil {
+			return nil, err
+		}
+		entry.RequestBody = redactBody(req.Header, body)
+	}
+	log.Printf("[DEBUG] Spectro Cloud API request %s %s\n%s", entry.Method, entry.URL, entry.RequestBody)
+
+	resp, err := t.next.RoundTrip(req)
+	entry.DurationMs = time.Since(entry.Time).Milliseconds()
+
+	if err != nil {
+		entry.Error = err.Error()
+		log.Printf("[DEBUG] Spectro Cloud API request %s %s failed: %v", entry.Method, entry.URL, err)
+	} else {
+		entry.Status = resp.StatusCode
+		entry.ResponseHeaders = redactHeaders(resp.Header)
+		body, readErr := ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if readErr != nil {
+			return nil, readErr
+		}
+		entry.ResponseBody = redactBody(resp.Header, body)
+		log.Printf("[DEBUG] Spectro Cloud API response %s %s: %d in %dms\n%s", entry.Method, entry.URL, entry.Status, entry.DurationMs, entry.ResponseBody)
+	}
+
+	if t.trace != nil {
+		t.trace.write(entry)
+	}
+	return resp, err
+}
+
+// readRequestBody returns the body of req, leaving it readable for the next
+// round tripper
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return ioutil.ReadAll(body)
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.Body, _ = req.GetBody()
+	return body, nil
+}
+
+func redactHeaders(header http.Header) http.Header {
+	redactedHeader := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if redactedHeader.Get(name) != "" {
+			redactedHeader.Set(name, redacted)
+		}
+	}
+	return redactedHeader
+}
+
+// redactBody returns body with the values of sensitive keys redacted. Bodies
+// that are not JSON, eg kubeconfigs and manifests, are not logged at all.
+func redactBody(header http.Header, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var value interface{}
+	if !strings.Contains(header.Get("Content-Type"), "json") || json.Unmarshal(body, &value) != nil {
+		return fmt.Sprintf("<%d bytes of %s redacted>", len(body), header.Get("Content-Type"))
+	}
+
+	out, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return redacted
+	}
+	return string(out)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isSensitiveKey(key) {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,36 +1,56 @@
 package client
 
 import (
-	"github.com/spectrocloud/hapi/apiutil"
+	"context"
+
 	"github.com/spectrocloud/hapi/models"
+	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client/herr"
 
 	clusterC "github.com/spectrocloud/hapi/spectrocluster/client/v1"
 )
 
-func (h *V1Client) CreateIpPool(pcgUID string, pool *models.V1IPPoolInputEntity) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetPrivateCloudGateway(ctx context.Context, uid string) (*models.V1Overlord, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return "", nil
+		return nil, err
 	}
 
-	params := clusterC.NewV1OverlordsUIDPoolCreateParams().WithUID(pcgUID).WithBody(pool)
-	if resp, err := client.V1OverlordsUIDPoolCreate(params); err != nil {
+	params := clusterC.NewV1OverlordsUIDGetParamsWithContext(h.projectContext(ctx)).WithUID(uid)
+	success, err := client.V1OverlordsUIDGet(params)
+	if err != nil {
+		if herr.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, herr.Wrap(err)
+	}
+
+	return success.Payload, nil
+}
+
+func (h *V1Client) CreateIpPool(ctx context.Context, pcgUID string, pool *models.V1IPPoolInputEntity) (string, error) {
+	client, err := h.getClusterClient(ctx)
+	if err != nil {
 		return "", err
+	}
+
+	params := clusterC.NewV1OverlordsUIDPoolCreateParamsWithContext(h.projectContext(ctx)).WithUID(pcgUID).WithBody(pool)
+	if resp, err := client.V1OverlordsUIDPoolCreate(params); err != nil {
+		return "", herr.Wrap(err)
 	} else {
 		return *resp.Payload.UID, nil
 	}
 }
 
-func (h *V1Client) GetIpPool(pcgUID, poolUID string) (*models.V1IPPoolEntity, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetIpPool(ctx context.Context, pcgUID, poolUID string) (*models.V1IPPoolEntity, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1OverlordsUIDPoolsListParams().WithUID(pcgUID)
+	params := clusterC.NewV1OverlordsUIDPoolsListParamsWithContext(h.projectContext(ctx)).WithUID(pcgUID)
 	if listResp, err := client.V1OverlordsUIDPoolsList(params); err != nil {
-		if v1Err := apiutil.ToV1ErrorObj(err); v1Err.Code != "ResourceNotFound" {
-			return nil, err
+		if !herr.IsNotFound(err) {
+			return nil, herr.Wrap(err)
 		}
 	} else if listResp.Payload != nil && listResp.Payload.Items != nil {
 		for _, pool := range listResp.Payload.Items {
@@ -42,25 +62,25 @@ func (h *V1Client) GetIpPool(pcgUID, poolUID string) (*models.V1IPPoolEntity, er
 	return nil, nil
 }
 
-func (h *V1Client) UpdateIpPool(pcgUID, poolUID string, pool *models.V1IPPoolInputEntity) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) UpdateIpPool(ctx context.Context, pcgUID, poolUID string, pool *models.V1IPPoolInputEntity) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1OverlordsUIDPoolUpdateParams().WithUID(pcgUID).
+	params := clusterC.NewV1OverlordsUIDPoolUpdateParamsWithContext(h.projectContext(ctx)).WithUID(pcgUID).
 		WithBody(pool).WithPoolUID(poolUID)
 	_, err = client.V1OverlordsUIDPoolUpdate(params)
-	return err
+	return herr.Wrap(err)
 }
 
-func (h *V1Client) DeleteIpPool(pcgUID, poolUID string) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) DeleteIpPool(ctx context.Context, pcgUID, poolUID string) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	params := clusterC.NewV1OverlordsUIDPoolDeleteParams().WithUID(pcgUID).WithPoolUID(poolUID)
+	params := clusterC.NewV1OverlordsUIDPoolDeleteParamsWithContext(h.projectContext(ctx)).WithUID(pcgUID).WithPoolUID(poolUID)
 	_, err = client.V1OverlordsUIDPoolDelete(params)
-	return err
+	return herr.Wrap(err)
 }
@@ -1,37 +1,41 @@
 package client
 
 import (
+	"context"
+
+	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client/herr"
+
 	"github.com/spectrocloud/hapi/models"
 
 	userC "github.com/spectrocloud/hapi/user/client/v1"
 )
 
-func (h *V1Client) CreateProject(body *models.V1ProjectEntity) (string, error) {
-	client, err := h.getUserClient()
+func (h *V1Client) CreateProject(ctx context.Context, body *models.V1ProjectEntity) (string, error) {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := userC.NewV1ProjectsCreateParams().WithBody(body)
+	params := userC.NewV1ProjectsCreateParamsWithContext(h.projectContext(ctx)).WithBody(body)
 	success, err := client.V1ProjectsCreate(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
 }
 
-func (h *V1Client) GetProject(uid string) (*models.V1Project, error) {
-	client, err := h.getUserClient()
+func (h *V1Client) GetProject(ctx context.Context, uid string) (*models.V1Project, error) {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	limit := int64(5000)
-	params := userC.NewV1ProjectsListParams().WithLimit(&limit)
+	params := userC.NewV1ProjectsListParamsWithContext(h.projectContext(ctx)).WithLimit(&limit)
 	projects, err := client.V1ProjectsList(params)
 	if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	for _, project := range projects.Payload.Items {
@@ -43,31 +47,31 @@ func (h *V1Client) GetProject(uid string) (*models.V1Project, error) {
 	return nil, nil
 }
 
-func (h *V1Client) UpdateProject(uid string, body *models.V1ProjectEntity) error {
-	client, err := h.getUserClient()
+func (h *V1Client) UpdateProject(ctx context.Context, uid string, body *models.V1ProjectEntity) error {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	params := userC.NewV1ProjectsUIDUpdateParams().WithBody(body).WithUID(uid)
+	params := userC.NewV1ProjectsUIDUpdateParamsWithContext(h.projectContext(ctx)).WithBody(body).WithUID(uid)
 	_, err = client.V1ProjectsUIDUpdate(params)
 	if err != nil {
-		return err
+		return herr.Wrap(err)
 	}
 
 	return nil
 }
 
-func (h *V1Client) DeleteProject(uid string) error {
-	client, err := h.getUserClient()
+func (h *V1Client) DeleteProject(ctx context.Context, uid string) error {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	params := userC.NewV1ProjectsUIDDeleteParams().WithUID(uid)
+	params := userC.NewV1ProjectsUIDDeleteParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	_, err = client.V1ProjectsUIDDelete(params)
 	if err != nil {
-		return err
+		return herr.Wrap(err)
 	}
 
 	return nil
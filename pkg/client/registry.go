@@ -1,21 +1,25 @@
 package client
 
 import (
+	"context"
+
+	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client/herr"
+
 	"fmt"
 	"github.com/spectrocloud/hapi/models"
 	clusterC "github.com/spectrocloud/hapi/spectrocluster/client/v1"
 )
 
-func (h *V1Client) GetRegistryOciByName(registryName string) (*models.V1OciRegistry, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetRegistryOciByName(ctx context.Context, registryName string) (*models.V1OciRegistry, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1OciRegistriesSummaryParams()
+	params := clusterC.NewV1OciRegistriesSummaryParamsWithContext(h.projectContext(ctx))
 	registries, err := client.V1OciRegistriesSummary(params)
 	if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	for _, registry := range registries.Payload.Items {
@@ -28,62 +32,62 @@ func (h *V1Client) GetRegistryOciByName(registryName string) (*models.V1OciRegis
 }
 
 
-func (h *V1Client) GetRegistryOci(uid string) (*models.V1EcrRegistry, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) GetRegistryOci(ctx context.Context, uid string) (*models.V1EcrRegistry, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := clusterC.NewV1EcrRegistriesUIDGetParams().WithUID(uid)
+	params := clusterC.NewV1EcrRegistriesUIDGetParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	response, err := client.V1EcrRegistriesUIDGet(params)
 	if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return response.Payload, nil
 }
 
 
-func (h *V1Client) CreateOciEcrRegistry(registry *models.V1EcrRegistry) (string, error) {
-	client, err := h.getClusterClient()
+func (h *V1Client) CreateOciEcrRegistry(ctx context.Context, registry *models.V1EcrRegistry) (string, error) {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := clusterC.NewV1EcrRegistriesCreateParams().WithBody(registry)
+	params := clusterC.NewV1EcrRegistriesCreateParamsWithContext(h.projectContext(ctx)).WithBody(registry)
 	if resp, err := client.V1EcrRegistriesCreate(params); err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	} else {
 		return *resp.Payload.UID, nil
 	}
 }
 
 
-func (h *V1Client) UpdateEcrRegistry(uid string, registry *models.V1EcrRegistry) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) UpdateEcrRegistry(ctx context.Context, uid string, registry *models.V1EcrRegistry) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	params := clusterC.NewV1EcrRegistriesUIDUpdateParams().WithBody(registry).WithUID(uid)
+	params := clusterC.NewV1EcrRegistriesUIDUpdateParamsWithContext(h.projectContext(ctx)).WithBody(registry).WithUID(uid)
 	_, err = client.V1EcrRegistriesUIDUpdate(params)
 	if err != nil {
-		return err
+		return herr.Wrap(err)
 	}
 
 	return nil
 }
 
-func (h *V1Client) DeleteRegistry(uid string) error {
-	client, err := h.getClusterClient()
+func (h *V1Client) DeleteRegistry(ctx context.Context, uid string) error {
+	client, err := h.getClusterClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	params := clusterC.NewV1BasicOciRegistriesUIDDeleteParams().WithUID(uid)
+	params := clusterC.NewV1BasicOciRegistriesUIDDeleteParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	_, err = client.V1BasicOciRegistriesUIDDelete(params)
 	if err != nil {
-		return err
+		return herr.Wrap(err)
 	}
 
 	return nil
@@ -0,0 +1,244 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	WaitMin:     time.Millisecond,
+	WaitMax:     10 * time.Millisecond,
+}
+
+// statusServer answers with the given statuses in turn, then with 200, and
+// records the body of every request
+type statusServer struct {
+	statuses   []int
+	retryAfter string
+
+	lock   sync.Mutex
+	bodies []string
+}
+
+func (s *statusServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	s.lock.Lock()
+	attempt := len(s.bodies)
+	s.bodies = append(s.bodies, string(body))
+	s.lock.Unlock()
+
+	if attempt < len(s.statuses) {
+		if s.retryAfter != "" {
+			w.Header().Set("Retry-After", s.retryAfter)
+		}
+		w.WriteHeader(s.statuses[attempt])
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *statusServer) attempts() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.bodies)
+}
+
+func doRetried(ctx context.Context, t *testing.T, policy RetryPolicy, method, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader(`{"name": "test"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := newRetryTransport(http.DefaultTransport, policy).RoundTrip(req)
+	if resp != nil {
+		_ = resp.Body.Close()
+	}
+	return resp, err
+}
+
+func TestRetryTransportThrottled(t *testing.T) {
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		t.Run(method, func(t *testing.T) {
+			server := &statusServer{statuses: []int{http.StatusTooManyRequests, http.StatusTooManyRequests}}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			resp, err := doRetried(context.Background(), t, testRetryPolicy, method, ts.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+			if server.attempts() != 3 {
+				t.Errorf("got %d attempts, want 3", server.attempts())
+			}
+			for i, body := range server.bodies {
+				if body != `{"name": "test"}` {
+					t.Errorf("attempt %d got body %q", i, body)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransportServerError(t *testing.T) {
+	tests := []struct {
+		method   string
+		status   int
+		attempts int
+	}{
+		{http.MethodGet, http.StatusServiceUnavailable, 2},
+		{http.MethodPut, http.StatusBadGateway, 2},
+		{http.MethodDelete, http.StatusInternalServerError, 2},
+		{http.MethodGet, http.StatusNotImplemented, 1},
+		{http.MethodPost, http.StatusServiceUnavailable, 1},
+		{http.MethodPatch, http.StatusInternalServerError, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+http.StatusText(tt.status), func(t *testing.T) {
+			server := &statusServer{statuses: []int{tt.status}}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			resp, err := doRetried(context.Background(), t, testRetryPolicy, tt.method, ts.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if server.attempts() != tt.attempts {
+				t.Errorf("got %d attempts, want %d", server.attempts(), tt.attempts)
+			}
+			if tt.attempts == 1 && resp.StatusCode != tt.status {
+				t.Errorf("got status %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
+
+func TestRetryTransportRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter func() string
+	}{
+		{"seconds", func() string { return "1" }},
+		{"date", func() string { return time.Now().Add(2 * time.Second).UTC().Format(http.TimeFormat) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retryAfter := tt.retryAfter()
+			server := &statusServer{statuses: []int{http.StatusTooManyRequests}, retryAfter: retryAfter}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			policy := testRetryPolicy
+			policy.WaitMax = 5 * time.Second
+			start := time.Now()
+			resp, err := doRetried(context.Background(), t, policy, http.MethodGet, ts.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+			// dates only have a precision of a second
+			if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+				t.Errorf("retried after %s, Retry-After %s was not honoured", elapsed, retryAfter)
+			}
+		})
+	}
+}
+
+func TestRetryTransportRetryAfterCapped(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter func() string
+	}{
+		{"seconds", func() string { return "86400" }},
+		{"future date", func() string { return time.Now().Add(24 * time.Hour).UTC().Format(http.TimeFormat) }},
+		{"past date", func() string { return time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat) }},
+		{"negative", func() string { return "-10" }},
+		{"invalid", func() string { return "soon" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &statusServer{statuses: []int{http.StatusTooManyRequests}, retryAfter: tt.retryAfter()}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			start := time.Now()
+			resp, err := doRetried(context.Background(), t, testRetryPolicy, http.MethodGet, ts.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("retried after %s, want at most %s", elapsed, testRetryPolicy.WaitMax)
+			}
+		})
+	}
+}
+
+func TestRetryTransportMaxAttempts(t *testing.T) {
+	server := &statusServer{statuses: []int{503, 503, 503, 503, 503, 503}}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	policy := testRetryPolicy
+	policy.MaxAttempts = 2
+	resp, err := doRetried(context.Background(), t, policy, http.MethodGet, ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+	if server.attempts() != policy.MaxAttempts+1 {
+		t.Errorf("got %d attempts, want %d", server.attempts(), policy.MaxAttempts+1)
+	}
+}
+
+func TestRetryTransportDisabled(t *testing.T) {
+	server := &statusServer{statuses: []int{http.StatusTooManyRequests}}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	if _, err := doRetried(context.Background(), t, RetryPolicy{}, http.MethodGet, ts.URL); err != nil {
+		t.Fatal(err)
+	}
+	if server.attempts() != 1 {
+		t.Errorf("got %d attempts, want 1", server.attempts())
+	}
+}
+
+func TestRetryTransportContextCancelled(t *testing.T) {
+	server := &statusServer{statuses: []int{429, 429, 429, 429}, retryAfter: "60"}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	policy := testRetryPolicy
+	policy.WaitMax = time.Minute
+	start := time.Now()
+	_, err := doRetried(ctx, t, policy, http.MethodGet, ts.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("returned after %s, the cancelled context was not honoured", elapsed)
+	}
+	if server.attempts() != 1 {
+		t.Errorf("got %d attempts, want 1", server.attempts())
+	}
+}
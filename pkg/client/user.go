@@ -1,6 +1,10 @@
 package client
 
 import (
+	"context"
+
+	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client/herr"
+
 	"fmt"
 
 	userC "github.com/spectrocloud/hapi/user/client/v1"
@@ -8,16 +12,16 @@ import (
 	"github.com/spectrocloud/hapi/models"
 )
 
-func (h *V1Client) GetRole(roleName string) (*models.V1Role, error) {
-	client, err := h.getUserClient()
+func (h *V1Client) GetRole(ctx context.Context, roleName string) (*models.V1Role, error) {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := userC.NewV1RolesListParams()
+	params := userC.NewV1RolesListParamsWithContext(h.projectContext(ctx))
 	roles, err := client.V1RolesList(params)
 	if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	for _, role := range roles.Payload.Items {
@@ -29,16 +33,16 @@ func (h *V1Client) GetRole(roleName string) (*models.V1Role, error) {
 	return nil, fmt.Errorf("role '%s' not found", roleName)
 }
 
-func (h *V1Client) GetUser(name string) (*models.V1User, error) {
-	client, err := h.getUserClient()
+func (h *V1Client) GetUser(ctx context.Context, name string) (*models.V1User, error) {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := userC.NewV1UsersListParams()
+	params := userC.NewV1UsersListParamsWithContext(h.projectContext(ctx))
 	users, err := client.V1UsersList(params)
 	if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	for _, user := range users.Payload.Items {
@@ -50,92 +54,113 @@ func (h *V1Client) GetUser(name string) (*models.V1User, error) {
 	return nil, fmt.Errorf("user '%s' not found", name)
 }
 
-func (h *V1Client) CreateTeam(team *models.V1Team) (string, error) {
-	client, err := h.getUserClient()
+func (h *V1Client) CreateTeam(ctx context.Context, team *models.V1Team) (string, error) {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	params := userC.NewV1TeamsCreateParams().WithBody(team)
+	params := userC.NewV1TeamsCreateParamsWithContext(h.projectContext(ctx)).WithBody(team)
 	success, err := client.V1TeamsCreate(params)
 	if err != nil {
-		return "", err
+		return "", herr.Wrap(err)
 	}
 
 	return *success.Payload.UID, nil
 }
 
-func (h *V1Client) UpdateTeam(uid string, team *models.V1Team) error {
-	client, err := h.getUserClient()
+func (h *V1Client) UpdateTeam(ctx context.Context, uid string, team *models.V1Team) error {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	params := userC.NewV1TeamsUIDUpdateParams().WithBody(team).WithUID(uid)
+	params := userC.NewV1TeamsUIDUpdateParamsWithContext(h.projectContext(ctx)).WithBody(team).WithUID(uid)
 	_, err = client.V1TeamsUIDUpdate(params)
 	if err != nil {
-		return err
+		return herr.Wrap(err)
 	}
 
 	return nil
 }
 
-func (h *V1Client) DeleteTeam(uid string) error {
-	client, err := h.getUserClient()
+func (h *V1Client) DeleteTeam(ctx context.Context, uid string) error {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	params := userC.NewV1TeamsUIDDeleteParams().WithUID(uid)
+	params := userC.NewV1TeamsUIDDeleteParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	_, err = client.V1TeamsUIDDelete(params)
 	if err != nil {
-		return err
+		return herr.Wrap(err)
 	}
 
 	return nil
 }
 
-func (h *V1Client) AssociateTeamProjectRole(uid string, body *models.V1ProjectRolesPatch) error {
-	client, err := h.getUserClient()
+func (h *V1Client) AssociateTeamProjectRole(ctx context.Context, uid string, body *models.V1ProjectRolesPatch) error {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return err
 	}
 
-	params := userC.NewV1TeamsProjectRolesPutParams().WithUID(uid).WithBody(body)
+	params := userC.NewV1TeamsProjectRolesPutParamsWithContext(h.projectContext(ctx)).WithUID(uid).WithBody(body)
 	_, err = client.V1TeamsProjectRolesPut(params)
 	if err != nil {
-		return err
+		return herr.Wrap(err)
 	}
 
 	return nil
 }
 
-func (h *V1Client) GetTeamProjectRoleAssociation(uid string) (*models.V1ProjectRolesEntity, error) {
-	client, err := h.getUserClient()
+func (h *V1Client) GetTeamProjectRoleAssociation(ctx context.Context, uid string) (*models.V1ProjectRolesEntity, error) {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := userC.NewV1TeamsProjectRolesParams().WithUID(uid)
+	params := userC.NewV1TeamsProjectRolesParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	success, err := client.V1TeamsProjectRoles(params)
 	if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return success.Payload, nil
 }
 
-func (h *V1Client) GetTeam(uid string) (*models.V1Team, error) {
-	client, err := h.getUserClient()
+func (h *V1Client) GetTeam(ctx context.Context, uid string) (*models.V1Team, error) {
+	client, err := h.getUserClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	params := userC.NewV1TeamsUIDGetParams().WithUID(uid)
+	params := userC.NewV1TeamsUIDGetParamsWithContext(h.projectContext(ctx)).WithUID(uid)
 	success, err := client.V1TeamsUIDGet(params)
 	if err != nil {
-		return nil, err
+		return nil, herr.Wrap(err)
 	}
 
 	return success.Payload, nil
 }
+
+func (h *V1Client) GetTeamByName(ctx context.Context, name string) (*models.V1Team, error) {
+	client, err := h.getUserClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := userC.NewV1TeamsListParamsWithContext(h.projectContext(ctx))
+	teams, err := client.V1TeamsList(params)
+	if err != nil {
+		return nil, herr.Wrap(err)
+	}
+
+	for _, team := range teams.Payload.Items {
+		if team.Metadata.Name == name {
+			return team, nil
+		}
+	}
+
+	return nil, fmt.Errorf("team '%s' not found", name)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"log"
+	"sort"
 	"strings"
 	"time"
 
@@ -36,19 +37,29 @@ var resourceClusterCreatePendingStates = []string{
 	"Pending",
 	"Provisioning",
 	"Importing",
+	"Updating",
 }
 
-//var resourceClusterUpdatePendingStates = []string{
-//	"backing-up",
-//	"modifying",
-//	"resetting-master-credentials",
-//	"upgrading",
-//}
-func waitForClusterDeletion(ctx context.Context, c *client.V1Client, id string, timeout time.Duration) error {
+var resourceClusterUpdatePendingStates = []string{
+	"Pending",
+	"Provisioning",
+	"Importing",
+	"Updating",
+}
+
+// DefaultErrorGracePeriod is how long, in minutes, a cluster may report failures
+// before a wait on it is aborted
+var DefaultErrorGracePeriod = 10
+
+// DefaultForceDeleteDelay is how long, in minutes, a cluster may be deleting
+// before it is force deleted
+var DefaultForceDeleteDelay = 20
+
+func waitForClusterDeletion(ctx context.Context, c *client.V1Client, d *schema.ResourceData, timeout time.Duration) error {
 	stateConf := &resource.StateChangeConf{
 		Pending:    resourceClusterDeletePendingStates,
 		Target:     nil, // wait for deleted
-		Refresh:    resourceClusterStateRefreshFunc(c, id),
+		Refresh:    resourceClusterDeleteRefreshFunc(ctx, c, d.Id()),
 		Timeout:    timeout,
 		MinTimeout: 10 * time.Second,
 		Delay:      30 * time.Second,
@@ -59,12 +70,34 @@ func waitForClusterDeletion(ctx context.Context, c *client.V1Client, id string,
 	return err
 }
 
-func updateProfiles(c *client.V1Client, d *schema.ResourceData) error {
+// machinePoolStatusFunc returns the status of every machine of a machine pool
+type machinePoolStatusFunc func(ctx context.Context, c *client.V1Client, cloudConfigId, machinePoolName string) ([]*models.V1CloudMachineStatus, error)
+
+// waitForClusterUpdate waits for a day-2 change to roll out: the cluster is
+// Running and every machine pool has its desired count of running, healthy machines.
+func waitForClusterUpdate(ctx context.Context, c *client.V1Client, d *schema.ResourceData, poolStatus machinePoolStatusFunc) error {
+	stateConf := &resource.StateChangeConf{
+		Pending:    resourceClusterUpdatePendingStates,
+		Target:     []string{"Running"},
+		Refresh:    resourceClusterStateRefreshFunc(ctx, c, d, poolStatus),
+		Timeout:    d.Timeout(schema.TimeoutUpdate) - 1*time.Minute,
+		MinTimeout: 10 * time.Second,
+		Delay:      30 * time.Second,
+		// the cluster may still look converged until the change is picked up
+		ContinuousTargetOccurence: 2,
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+
+	return err
+}
+
+func updateProfiles(ctx context.Context, c *client.V1Client, d *schema.ResourceData) error {
 	log.Printf("Updating profiles")
 	body := &models.V1SpectroClusterProfiles{
 		Profiles: toProfiles(d),
 	}
-	if err := c.UpdateClusterProfileValues(d.Id(), body); err != nil {
+	if err := c.UpdateClusterProfileValues(ctx, d.Id(), body); err != nil {
 		return err
 	}
 	return nil
@@ -149,9 +182,9 @@ func flattenBackupPolicy(policy *models.V1ClusterBackupConfig) []interface{} {
 	return result
 }
 
-func updateBackupPolicy(c *client.V1Client, d *schema.ResourceData) error {
+func updateBackupPolicy(ctx context.Context, c *client.V1Client, d *schema.ResourceData) error {
 	if policy := toBackupPolicy(d); policy != nil {
-		return c.ApplyClusterBackupConfig(d.Id(), policy)
+		return c.ApplyClusterBackupConfig(ctx, d.Id(), policy)
 	}
 	return nil
 }
@@ -202,9 +235,9 @@ func flattenScanPolicy(driverSpec map[string]models.V1ComplianceScanDriverSpec)
 	return result
 }
 
-func updateScanPolicy(c *client.V1Client, d *schema.ResourceData) error {
+func updateScanPolicy(ctx context.Context, c *client.V1Client, d *schema.ResourceData) error {
 	if policy := toScanPolicy(d); policy != nil {
-		return c.ApplyClusterScanConfig(d.Id(), policy)
+		return c.ApplyClusterScanConfig(ctx, d.Id(), policy)
 	}
 	return nil
 }
@@ -241,6 +274,181 @@ func toProfiles(d *schema.ResourceData) []*models.V1SpectroClusterProfileEntity
 	return resp
 }
 
+// flattenCommonAttributes sets the attributes shared by all cluster resources
+func flattenCommonAttributes(d *schema.ResourceData, cluster *models.V1SpectroCluster) error {
+	if err := d.Set("name", cluster.Metadata.Name); err != nil {
+		return err
+	}
+	if err := d.Set("tags", flattenTags(cluster.Metadata.Labels)); err != nil {
+		return err
+	}
+	if _, found := d.GetOk("cluster_profile_id"); !found {
+		if err := d.Set("cluster_profile", flattenClusterProfiles(d, cluster.Spec.ClusterProfileTemplates)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flattenClusterProfiles keeps the order and pack overrides already in state
+// and appends any profile attached to the cluster outside of terraform
+func flattenClusterProfiles(d *schema.ResourceData, templates []*models.V1ClusterProfileTemplate) []interface{} {
+	attached := make(map[string]bool)
+	for _, t := range templates {
+		attached[t.UID] = true
+	}
+
+	profiles := make([]interface{}, 0, len(templates))
+	seen := make(map[string]bool)
+	for _, p := range d.Get("cluster_profile").([]interface{}) {
+		profile := p.(map[string]interface{})
+		if id := profile["id"].(string); attached[id] && !seen[id] {
+			profiles = append(profiles, profile)
+			seen[id] = true
+		}
+	}
+	for _, t := range templates {
+		if !seen[t.UID] {
+			profiles = append(profiles, map[string]interface{}{
+				"id": t.UID,
+			})
+			seen[t.UID] = true
+		}
+	}
+
+	return profiles
+}
+
+// flattenClusterStatus sets the computed status attributes of the cluster.
+// Health only reflects the conditions here, flattenMachinePoolStatus adds the machines.
+func flattenClusterStatus(d *schema.ResourceData, cluster *models.V1SpectroCluster, kubeconfig string) error {
+	state := ""
+	conditions := make([]interface{}, 0)
+	if cluster.Status != nil {
+		state = cluster.Status.State
+		for _, condition := range cluster.Status.Conditions {
+			if condition == nil {
+				continue
+			}
+			c := map[string]interface{}{
+				"reason":  condition.Reason,
+				"message": condition.Message,
+			}
+			if condition.Type != nil {
+				c["type"] = *condition.Type
+			}
+			if condition.Status != nil {
+				c["status"] = *condition.Status
+			}
+			conditions = append(conditions, c)
+		}
+	}
+
+	attributes := map[string]interface{}{
+		"status":             state,
+		"health":             clusterHealth(cluster),
+		"conditions":         conditions,
+		"kubernetes_version": clusterKubernetesVersion(cluster),
+		"api_endpoint":       kubeconfigServer(kubeconfig),
+		"creation_timestamp": clusterCreationTimestamp(cluster),
+	}
+	for key, value := range attributes {
+		if err := d.Set(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func clusterState(cluster *models.V1SpectroCluster) string {
+	if cluster.Status == nil {
+		return ""
+	}
+	return cluster.Status.State
+}
+
+func clusterHealth(cluster *models.V1SpectroCluster) string {
+	if cluster.Status == nil {
+		return "Unknown"
+	} else if len(clusterConditionFailures(cluster)) > 0 {
+		return "Unhealthy"
+	} else if cluster.Status.State == "Running" {
+		return "Healthy"
+	}
+	return "Unknown"
+}
+
+// clusterKubernetesVersion returns the tag of the k8s layer of the cluster profiles
+func clusterKubernetesVersion(cluster *models.V1SpectroCluster) string {
+	kubernetesVersion := ""
+	if cluster.Spec != nil {
+		for _, template := range cluster.Spec.ClusterProfileTemplates {
+			for _, pack := range template.Packs {
+				if pack.Layer == "k8s" {
+					kubernetesVersion = pack.Tag
+				}
+			}
+		}
+	}
+	return kubernetesVersion
+}
+
+func clusterCreationTimestamp(cluster *models.V1SpectroCluster) string {
+	if cluster.Metadata == nil {
+		return ""
+	}
+	return time.Time(cluster.Metadata.CreationTimestamp).UTC().Format(time.RFC3339)
+}
+
+// kubeconfigServer returns the API server of the first cluster of a kubeconfig
+func kubeconfigServer(kubeconfig string) string {
+	for _, line := range strings.Split(kubeconfig, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "server:") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "server:")), `"'`)
+		}
+	}
+	return ""
+}
+
+// errCloudConfigNotFound fails the read of a cluster without a cloud config,
+// such as an imported cluster whose cloud config was deleted
+func errCloudConfigNotFound(d *schema.ResourceData, configUID string) diag.Diagnostics {
+	if configUID == "" {
+		return diag.FromErr(fmt.Errorf("cluster '%s' has no cloud config", d.Id()))
+	}
+	return diag.FromErr(fmt.Errorf("cloud config '%s' of cluster '%s' not found", configUID, d.Id()))
+}
+
+// flattenMachinePoolStatus sets the ready_count of each flattened machine pool
+// and marks the cluster Unhealthy when any machine failed or is unhealthy
+func flattenMachinePoolStatus(ctx context.Context, c *client.V1Client, d *schema.ResourceData, cloudConfigId string, machinePools []interface{}, poolStatus machinePoolStatusFunc) error {
+	failures := make([]string, 0)
+	for _, mp := range machinePools {
+		machinePool := mp.(map[string]interface{})
+		name := machinePool["name"].(string)
+
+		statuses, err := poolStatus(ctx, c, cloudConfigId, name)
+		if err != nil {
+			return err
+		}
+
+		readyCount := 0
+		for _, status := range statuses {
+			if isMachineReady(status) {
+				readyCount++
+			}
+		}
+		machinePool["ready_count"] = readyCount
+		failures = append(failures, machinePoolFailures(name, statuses)...)
+	}
+
+	if len(failures) > 0 {
+		return d.Set("health", "Unhealthy")
+	}
+	return nil
+}
+
 func toPack(pSrc interface{}) *models.V1PackValuesEntity {
 	p := pSrc.(map[string]interface{})
 
@@ -273,9 +481,25 @@ func toPack(pSrc interface{}) *models.V1PackValuesEntity {
 	return pack
 }
 
-func resourceClusterStateRefreshFunc(c *client.V1Client, id string) resource.StateRefreshFunc {
+// resourceClusterStateRefreshFunc returns the state of the cluster, reported as
+// Updating while Running but with machine pools that are not ready when
+// poolStatus is set. Failing cluster conditions and failed or unhealthy machines
+// abort the wait once they have lasted longer than the error grace period.
+func resourceClusterStateRefreshFunc(ctx context.Context, c *client.V1Client, d *schema.ResourceData, poolStatus machinePoolStatusFunc) resource.StateRefreshFunc {
+	id := d.Id()
+	gracePeriod := time.Duration(d.Get("error_grace_period_minutes").(int)) * time.Minute
+
+	machinePools := make(map[string]map[string]interface{})
+	if poolStatus != nil {
+		for _, mp := range machinePoolList(d.Get("machine_pool")) {
+			machinePool := mp.(map[string]interface{})
+			machinePools[machinePool["name"].(string)] = machinePool
+		}
+	}
+
+	var failingSince time.Time
 	return func() (interface{}, string, error) {
-		cluster, err := c.GetCluster(id)
+		cluster, err := c.GetCluster(ctx, id)
 		if err != nil {
 			return nil, "", err
 		} else if cluster == nil {
@@ -285,44 +509,314 @@ func resourceClusterStateRefreshFunc(c *client.V1Client, id string) resource.Sta
 		state := cluster.Status.State
 		log.Printf("Cluster state (%s): %s", id, state)
 
+		failures := clusterConditionFailures(cluster)
+		ready := true
+		if cluster.Spec != nil && cluster.Spec.CloudConfigRef != nil {
+			for name, machinePool := range machinePools {
+				statuses, err := poolStatus(ctx, c, cluster.Spec.CloudConfigRef.UID, name)
+				if err != nil {
+					return nil, "", err
+				}
+				failures = append(failures, machinePoolFailures(name, statuses)...)
+				if !isMachinePoolReady(statuses, machinePool) {
+					log.Printf("Machine pool %s of cluster %s is not ready", name, id)
+					ready = false
+				}
+			}
+		}
+
+		if len(failures) == 0 {
+			failingSince = time.Time{}
+		} else {
+			if failingSince.IsZero() {
+				failingSince = time.Now()
+			}
+			if time.Since(failingSince) >= gracePeriod {
+				return nil, "", fmt.Errorf("cluster '%s' is failing:\n  %s", id, strings.Join(failures, "\n  "))
+			}
+			log.Printf("[WARN] Cluster %s is failing, retrying until the grace period ends: %s", id, strings.Join(failures, "; "))
+		}
+
+		if state == "Running" && !ready {
+			return cluster, "Updating", nil
+		}
 		return cluster, state, nil
 	}
 }
 
+// resourceClusterDeleteRefreshFunc returns the state of a cluster being deleted.
+// Conditions turn false while the cluster is torn down, so they are not checked.
+func resourceClusterDeleteRefreshFunc(ctx context.Context, c *client.V1Client, id string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		cluster, err := c.GetCluster(ctx, id)
+		if err != nil {
+			return nil, "", err
+		} else if cluster == nil {
+			return nil, "Deleted", nil
+		}
+
+		state := cluster.Status.State
+		log.Printf("Cluster state (%s): %s", id, state)
+
+		return cluster, state, nil
+	}
+}
+
+// clusterConditionFailures returns the reasons and messages of the failed
+// conditions of the cluster
+func clusterConditionFailures(cluster *models.V1SpectroCluster) []string {
+	if cluster.Status == nil {
+		return make([]string, 0)
+	}
+	return conditionFailures("", cluster.Status.Conditions)
+}
+
+// conditionFailures returns the failed conditions, prefixed with scope. A
+// condition fails when it is False with a reason, conditions that are simply
+// not met yet, eg while provisioning, are Unknown or have no reason.
+func conditionFailures(scope string, conditions []*models.V1ClusterCondition) []string {
+	failures := make([]string, 0)
+	for _, condition := range conditions {
+		if condition == nil || condition.Status == nil || *condition.Status != "False" || condition.Reason == "" {
+			continue
+		}
+
+		conditionType := ""
+		if condition.Type != nil {
+			conditionType = *condition.Type
+		}
+		failures = append(failures, fmt.Sprintf("%s%s: %s: %s", scope, conditionType, condition.Reason, condition.Message))
+	}
+	return failures
+}
+
+// machinePoolFailures returns the failed conditions of the machines of a pool,
+// listing identical ones once, and the machines that failed or are unhealthy
+func machinePoolFailures(name string, statuses []*models.V1CloudMachineStatus) []string {
+	failures := make([]string, 0)
+	seen := make(map[string]bool)
+	failed, unhealthy := 0, 0
+	for _, status := range statuses {
+		if status == nil {
+			continue
+		}
+		for _, failure := range conditionFailures(fmt.Sprintf("machine pool %s: ", name), status.Conditions) {
+			if !seen[failure] {
+				seen[failure] = true
+				failures = append(failures, failure)
+			}
+		}
+		if status.InstanceState == "Failed" {
+			failed++
+		} else if status.Health != nil && status.Health.State == "Unhealthy" {
+			unhealthy++
+		}
+	}
+
+	if failed > 0 {
+		failures = append(failures, fmt.Sprintf("machine pool %s: %d of %d machines failed", name, failed, len(statuses)))
+	}
+	if unhealthy > 0 {
+		failures = append(failures, fmt.Sprintf("machine pool %s: %d of %d machines unhealthy", name, unhealthy, len(statuses)))
+	}
+	return failures
+}
+
+func isMachinePoolReady(statuses []*models.V1CloudMachineStatus, machinePool map[string]interface{}) bool {
+	if !isMachinePoolSizeReady(machinePool, len(statuses)) {
+		return false
+	}
+	for _, status := range statuses {
+		if !isMachineReady(status) {
+			return false
+		}
+	}
+	return true
+}
+
+func isMachineReady(status *models.V1CloudMachineStatus) bool {
+	if status == nil || status.InstanceState != "Running" {
+		return false
+	}
+	return status.Health == nil || status.Health.State == "" || status.Health.State == "Healthy"
+}
+
 func resourceClusterDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 
-	err := c.DeleteCluster(d.Id())
+	if d.Get("deletion_protection").(bool) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Cluster is protected from deletion",
+			Detail:   fmt.Sprintf("Cluster '%s' has deletion_protection enabled, set it to false and apply before destroying or replacing the cluster", d.Id()),
+		})
+		return diags
+	}
+
+	start := time.Now()
+	if skipBackup, ok := d.Get("skip_backup_on_delete").(bool); ok && !skipBackup {
+		if err := backupClusterBeforeDelete(ctx, c, d, d.Timeout(schema.TimeoutDelete)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	err := c.DeleteCluster(ctx, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	if err := waitForClusterDeletion(ctx, c, d.Id(), d.Timeout(schema.TimeoutDelete)); err != nil {
-		return diag.FromErr(err)
+	timeout := d.Timeout(schema.TimeoutDelete) - time.Since(start)
+	if d.Get("force_delete").(bool) {
+		delay := time.Duration(d.Get("force_delete_delay").(int)) * time.Minute
+		if delay >= timeout {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Cluster can not be force deleted",
+				Detail:   fmt.Sprintf("Cluster '%s' is not force deleted, its force_delete_delay of %s is not shorter than the %s left of its delete timeout, raise the delete timeout or lower force_delete_delay", d.Id(), delay, timeout.Round(time.Second)),
+			})
+		} else {
+			// A cluster failing to delete is forced as well as one still deleting
+			if err := waitForClusterDeletion(ctx, c, d, delay); err == nil {
+				return diags
+			} else if ctx.Err() != nil {
+				return diag.FromErr(err)
+			} else {
+				log.Printf("[WARN] Cluster %s is not deleted after %s, forcing its deletion: %v", d.Id(), delay, err)
+			}
+
+			unmet, err := forceDeleteCluster(ctx, c, d.Id())
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			if len(unmet) > 0 {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  "Cluster was force deleted",
+					Detail:   fmt.Sprintf("Cluster '%s' was force deleted with these conditions unmet, check its cloud account for resources left behind:\n  %s", d.Id(), strings.Join(unmet, "\n  ")),
+				})
+			}
+			timeout -= delay
+		}
+	}
+
+	if err := waitForClusterDeletion(ctx, c, d, timeout); err != nil {
+		return append(diags, diag.FromErr(err)...)
 	}
 
 	return diags
 }
 
+// forceDeleteCluster force deletes a cluster stuck deleting and returns the
+// conditions it had not met yet
+func forceDeleteCluster(ctx context.Context, c *client.V1Client, uid string) ([]string, error) {
+	unmet := make([]string, 0)
+	cluster, err := c.GetCluster(ctx, uid)
+	if err != nil {
+		return nil, err
+	} else if cluster == nil {
+		return unmet, nil
+	}
+
+	if cluster.Status != nil {
+		for _, condition := range cluster.Status.Conditions {
+			if condition == nil || condition.Type == nil || (condition.Status != nil && *condition.Status == "True") {
+				continue
+			}
+			unmet = append(unmet, fmt.Sprintf("%s: %s", *condition.Type, condition.Message))
+		}
+	}
+
+	return unmet, c.ForceDeleteCluster(ctx, uid)
+}
+
+var clusterBackupPendingStates = []string{
+	"",
+	"New",
+	"InProgress",
+	"Uploading",
+}
+
+// backupClusterBeforeDelete takes a final backup of the cluster to the location
+// of its backup policy and waits for it to complete
+func backupClusterBeforeDelete(ctx context.Context, c *client.V1Client, d *schema.ResourceData, timeout time.Duration) error {
+	policy := toBackupPolicy(d)
+	if policy == nil {
+		return fmt.Errorf("cluster '%s' has skip_backup_on_delete disabled but no backup_policy to take the final backup with", d.Id())
+	}
+
+	config := &models.V1ClusterBackupConfig{
+		BackupLocationUID:       policy.BackupLocationUID,
+		BackupName:              fmt.Sprintf("%s-final-%d", policy.BackupPrefix, time.Now().Unix()),
+		DurationInHours:         policy.DurationInHours,
+		IncludeAllDisks:         policy.IncludeAllDisks,
+		IncludeClusterResources: policy.IncludeClusterResources,
+		Namespaces:              policy.Namespaces,
+	}
+	log.Printf("Taking final backup %s of cluster %s", config.BackupName, d.Id())
+	requestUID, err := c.CreateClusterBackupOnDemand(ctx, d.Id(), config)
+	if err != nil {
+		return err
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    clusterBackupPendingStates,
+		Target:     []string{"Completed"},
+		Refresh:    resourceClusterBackupStateRefreshFunc(ctx, c, d.Id(), requestUID),
+		Timeout:    timeout,
+		MinTimeout: 10 * time.Second,
+		Delay:      30 * time.Second,
+	}
+
+	_, err = stateConf.WaitForStateContext(ctx)
+	return err
+}
+
+func resourceClusterBackupStateRefreshFunc(ctx context.Context, c *client.V1Client, uid, requestUID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		backup, err := c.GetClusterBackupConfig(ctx, uid)
+		if err != nil {
+			return nil, "", err
+		} else if backup == nil {
+			return nil, "", fmt.Errorf("cluster '%s' has no backup configured", uid)
+		} else if backup.Status == nil {
+			return backup, "", nil
+		}
+
+		for _, status := range backup.Status.ClusterBackupStatuses {
+			if status.BackupRequestUID != requestUID || status.BackupState == nil {
+				continue
+			}
+
+			state := status.BackupState.State
+			log.Printf("Backup %s of cluster %s: %s", status.BackupName, uid, state)
+			if state == "Failed" || state == "PartiallyFailed" {
+				return nil, "", fmt.Errorf("final backup %s of cluster '%s' failed: %s", status.BackupName, uid, status.BackupState.Msg)
+			}
+			return status, state, nil
+		}
+		return backup, "", nil
+	}
+}
+
 func resourceMachinePoolAzureHash(v interface{}) int {
 	var buf bytes.Buffer
 	m := v.(map[string]interface{})
-	//d := m["disk"].([]interface{})[0].(map[string]interface{})
 	buf.WriteString(fmt.Sprintf("%t-", m["control_plane"].(bool)))
 	buf.WriteString(fmt.Sprintf("%t-", m["control_plane_as_worker"].(bool)))
 	buf.WriteString(fmt.Sprintf("%s-", m["name"].(string)))
-	buf.WriteString(fmt.Sprintf("%d-", m["count"].(int)))
+	hashMachinePoolSize(&buf, m)
 	buf.WriteString(fmt.Sprintf("%s-", m["instance_type"].(string)))
 	buf.WriteString(fmt.Sprintf("%s-", m["azs"].(*schema.Set).GoString()))
 
-	// TODO(saamalik) fix for disk
-	//buf.WriteString(fmt.Sprintf("%d-", d["size_gb"].(int)))
-	//buf.WriteString(fmt.Sprintf("%s-", d["type"].(string)))
+	if disks, found := m["disk"].([]interface{}); found && len(disks) > 0 && disks[0] != nil {
+		d := disks[0].(map[string]interface{})
+		buf.WriteString(fmt.Sprintf("%d-", d["size_gb"].(int)))
+		buf.WriteString(fmt.Sprintf("%s-", d["type"].(string)))
+	}
 
-	//d2 := m["disk"].([]interface{})
-	//d := d2[0].(map[string]interface{})
+	hashNodePoolMetadata(&buf, m)
 
 	return int(hash(buf.String()))
 }
@@ -331,11 +825,13 @@ func resourceMachinePoolAksHash(v interface{}) int {
 	var buf bytes.Buffer
 	m := v.(map[string]interface{})
 	buf.WriteString(fmt.Sprintf("%s-", m["name"].(string)))
-	buf.WriteString(fmt.Sprintf("%d-", m["count"].(int)))
+	hashMachinePoolSize(&buf, m)
 	buf.WriteString(fmt.Sprintf("%s-", m["instance_type"].(string)))
 	buf.WriteString(fmt.Sprintf("%d-", m["disk_size_gb"].(int)))
-	buf.WriteString(fmt.Sprintf("%d-", m["is_system_node_pool"].(bool)))
-	buf.WriteString(fmt.Sprintf("%d-", m["storage_account_type"].(string)))
+	buf.WriteString(fmt.Sprintf("%t-", m["is_system_node_pool"].(bool)))
+	buf.WriteString(fmt.Sprintf("%s-", m["storage_account_type"].(string)))
+	hashNodePoolMetadata(&buf, m)
+
 	return int(hash(buf.String()))
 }
 
@@ -346,10 +842,12 @@ func resourceMachinePoolGcpHash(v interface{}) int {
 	buf.WriteString(fmt.Sprintf("%t-", m["control_plane"].(bool)))
 	buf.WriteString(fmt.Sprintf("%t-", m["control_plane_as_worker"].(bool)))
 	buf.WriteString(fmt.Sprintf("%s-", m["name"].(string)))
-	buf.WriteString(fmt.Sprintf("%d-", m["count"].(int)))
+	hashMachinePoolSize(&buf, m)
 	buf.WriteString(fmt.Sprintf("%s-", m["instance_type"].(string)))
 	buf.WriteString(fmt.Sprintf("%s-", m["azs"].(*schema.Set).GoString()))
 
+	hashNodePoolMetadata(&buf, m)
+
 	return int(hash(buf.String()))
 }
 
@@ -359,12 +857,14 @@ func resourceMachinePoolAwsHash(v interface{}) int {
 	buf.WriteString(fmt.Sprintf("%t-", m["control_plane"].(bool)))
 	buf.WriteString(fmt.Sprintf("%t-", m["control_plane_as_worker"].(bool)))
 	buf.WriteString(fmt.Sprintf("%s-", m["name"].(string)))
-	buf.WriteString(fmt.Sprintf("%d-", m["count"].(int)))
+	hashMachinePoolSize(&buf, m)
 	buf.WriteString(fmt.Sprintf("%s-", m["instance_type"].(string)))
 	buf.WriteString(fmt.Sprintf("%s-", m["capacity_type"].(string)))
 	buf.WriteString(fmt.Sprintf("%s-", m["max_price"].(string)))
 	buf.WriteString(fmt.Sprintf("%s-", m["azs"].(*schema.Set).GoString()))
 
+	hashNodePoolMetadata(&buf, m)
+
 	return int(hash(buf.String()))
 }
 
@@ -373,15 +873,27 @@ func resourceMachinePoolEksHash(v interface{}) int {
 	m := v.(map[string]interface{})
 	buf.WriteString(fmt.Sprintf("%s-", m["name"].(string)))
 	buf.WriteString(fmt.Sprintf("%d-", m["disk_size_gb"].(int)))
-	buf.WriteString(fmt.Sprintf("%d-", m["count"].(int)))
+	hashMachinePoolSize(&buf, m)
 	buf.WriteString(fmt.Sprintf("%s-", m["instance_type"].(string)))
 	buf.WriteString(fmt.Sprintf("%s-", m["capacity_type"].(string)))
 	buf.WriteString(fmt.Sprintf("%s-", m["max_price"].(string)))
 
-	for i, j := range m["az_subnets"].(map[string]interface{}) {
-		buf.WriteString(fmt.Sprintf("%s-%s", i, j.(string)))
+	if azs, found := m["azs"].([]interface{}); found {
+		buf.WriteString(fmt.Sprintf("%v-", azs))
 	}
-	// TODO
+
+	// map iteration order is random, the hash must not be
+	azSubnets := m["az_subnets"].(map[string]interface{})
+	azNames := make([]string, 0, len(azSubnets))
+	for az := range azSubnets {
+		azNames = append(azNames, az)
+	}
+	sort.Strings(azNames)
+	for _, az := range azNames {
+		buf.WriteString(fmt.Sprintf("%s-%s-", az, azSubnets[az].(string)))
+	}
+
+	hashNodePoolMetadata(&buf, m)
 
 	return int(hash(buf.String()))
 }
@@ -402,6 +914,8 @@ func resourceMachinePoolVsphereHash(v interface{}) int {
 		buf.WriteString(fmt.Sprintf("%d-", ins["memory_mb"].(int)))
 	}
 
+	hashNodePoolMetadata(&buf, m)
+
 	return int(hash(buf.String()))
 }
 
@@ -419,6 +933,8 @@ func resourceMachinePoolOpenStackHash(v interface{}) int {
 	buf.WriteString(fmt.Sprintf("%s-", m["update_strategy"].(string)))
 	buf.WriteString(fmt.Sprintf("%s-", m["azs"].(*schema.Set).GoString()))
 
+	hashNodePoolMetadata(&buf, m)
+
 	return int(hash(buf.String()))
 }
 
@@ -465,6 +981,26 @@ func toOsPatchConfig(d *schema.ResourceData) *models.V1OsPatchConfig {
 	return nil
 }
 
+func flattenOsPatchConfig(d *schema.ResourceData, config *models.V1ClusterConfig) error {
+	if config == nil || config.MachineManagementConfig == nil || config.MachineManagementConfig.OsPatchConfig == nil {
+		return nil
+	}
+
+	osPatchConfig := config.MachineManagementConfig.OsPatchConfig
+	if err := d.Set("os_patch_on_boot", osPatchConfig.PatchOnBoot); err != nil {
+		return err
+	}
+	if err := d.Set("os_patch_schedule", osPatchConfig.Schedule); err != nil {
+		return err
+	}
+	if patchAfter := time.Time(osPatchConfig.OnDemandPatchAfter); !patchAfter.IsZero() {
+		if err := d.Set("os_patch_after", patchAfter.Format(time.RFC3339)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func validateOsPatchSchedule(data interface{}, _ cty.Path) diag.Diagnostics {
 	var diags diag.Diagnostics
 	if data != nil {
@@ -31,7 +31,7 @@ func dataSourceBackupStorageLocation() *schema.Resource {
 	}
 }
 
-func dataSourceBackupStorageLocationRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func dataSourceBackupStorageLocationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	// Warning or errors can be collected in a slice type
@@ -39,7 +39,7 @@ func dataSourceBackupStorageLocationRead(_ context.Context, d *schema.ResourceDa
 
 	projectScope := true
 
-	bsls, err := c.ListBackupStorageLocation(projectScope)
+	bsls, err := c.ListBackupStorageLocation(ctx, projectScope)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -35,13 +35,13 @@ func dataSourceCloudAccountAws() *schema.Resource {
 	}
 }
 
-func dataSourceCloudAccountAwsRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func dataSourceCloudAccountAwsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
-	accounts, err := c.GetCloudAccountsAws()
+	accounts, err := c.GetCloudAccountsAws(ctx)
 	if err != nil {
 		return diag.FromErr(err)
 	}
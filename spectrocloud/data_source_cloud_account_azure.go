@@ -38,13 +38,13 @@ func dataSourceCloudAccountAzure() *schema.Resource {
 	}
 }
 
-func dataSourceCloudAccountAzureRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func dataSourceCloudAccountAzureRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
-	accounts, err := c.GetCloudAccountsAzure()
+	accounts, err := c.GetCloudAccountsAzure(ctx)
 	if err != nil {
 		return diag.FromErr(err)
 	}
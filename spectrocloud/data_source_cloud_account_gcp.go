@@ -30,13 +30,13 @@ func dataSourceCloudAccountGcp() *schema.Resource {
 	}
 }
 
-func dataSourceCloudAccountGcpRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func dataSourceCloudAccountGcpRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
-	accounts, err := c.GetCloudAccountsGcp()
+	accounts, err := c.GetCloudAccountsGcp(ctx)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -30,13 +30,13 @@ func dataSourceCloudAccountOpenStack() *schema.Resource {
 	}
 }
 
-func dataSourceCloudAccountOpenStackRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func dataSourceCloudAccountOpenStackRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
-	accounts, err := c.GetCloudAccountsOpenStack()
+	accounts, err := c.GetCloudAccountsOpenStack(ctx)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -30,13 +30,13 @@ func dataSourceCloudAccountVsphere() *schema.Resource {
 	}
 }
 
-func dataSourceCloudAccountVsphereRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func dataSourceCloudAccountVsphereRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
-	accounts, err := c.GetCloudAccountsVsphere()
+	accounts, err := c.GetCloudAccountsVsphere(ctx)
 	if err != nil {
 		return diag.FromErr(err)
 	}
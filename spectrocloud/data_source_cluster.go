@@ -0,0 +1,261 @@
+package spectrocloud
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client"
+)
+
+func dataSourceCluster() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceClusterRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"cloud_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"cloud_config_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"cluster_profile_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"kubeconfig": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"health": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"conditions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"reason": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"message": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"kubernetes_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"api_endpoint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"creation_timestamp": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"machine_pool": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"control_plane": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"count": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"ready_count": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceClusterRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*client.V1Client)
+
+	var diags diag.Diagnostics
+
+	if project, ok := d.GetOk("project"); ok {
+		projectUID, err := c.GetProjectUID(ctx, project.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		ctx = client.ContextWithProject(ctx, projectUID)
+	}
+
+	cluster, err := c.GetClusterByName(ctx, d.Get("name").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(cluster.Metadata.UID)
+	if err := d.Set("cloud_type", cluster.Spec.CloudType); err != nil {
+		return diag.FromErr(err)
+	}
+
+	profileIDs := make([]string, 0, len(cluster.Spec.ClusterProfileTemplates))
+	for _, template := range cluster.Spec.ClusterProfileTemplates {
+		profileIDs = append(profileIDs, template.UID)
+	}
+	if err := d.Set("cluster_profile_ids", profileIDs); err != nil {
+		return diag.FromErr(err)
+	}
+
+	kubeconfig, err := c.GetClusterKubeConfig(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("kubeconfig", kubeconfig); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := flattenClusterStatus(d, cluster, kubeconfig); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if cluster.Spec.CloudConfigRef == nil {
+		return diags
+	}
+	configUID := cluster.Spec.CloudConfigRef.UID
+	if err := d.Set("cloud_config_id", configUID); err != nil {
+		return diag.FromErr(err)
+	}
+
+	machinePools, poolStatus, err := getClusterMachinePools(ctx, c, cluster.Spec.CloudType, configUID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := flattenMachinePoolStatus(ctx, c, d, configUID, machinePools, poolStatus); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("machine_pool", flattenMachinePoolSummaries(machinePools)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// getClusterMachinePools returns the flattened machine pools of a cluster of
+// any cloud type along with the status of their machines. A cloud config that
+// is missing, eg while the cluster is deleted, has no machine pools.
+func getClusterMachinePools(ctx context.Context, c *client.V1Client, cloudType, configUID string) ([]interface{}, machinePoolStatusFunc, error) {
+	switch cloudType {
+	case "aws":
+		config, err := c.GetCloudConfigAws(ctx, configUID)
+		if err != nil {
+			return nil, nil, err
+		} else if config == nil || config.Spec == nil {
+			return make([]interface{}, 0), nil, nil
+		}
+		return flattenMachinePoolConfigsAws(config.Spec.MachinePoolConfig), machinePoolStatusAws, nil
+	case "azure":
+		config, err := c.GetCloudConfigAzure(ctx, configUID)
+		if err != nil {
+			return nil, nil, err
+		} else if config == nil || config.Spec == nil {
+			return make([]interface{}, 0), nil, nil
+		}
+		return flattenMachinePoolConfigsAzure(config.Spec.MachinePoolConfig), machinePoolStatusAzure, nil
+	case "gcp":
+		config, err := c.GetCloudConfigGcp(ctx, configUID)
+		if err != nil {
+			return nil, nil, err
+		} else if config == nil || config.Spec == nil {
+			return make([]interface{}, 0), nil, nil
+		}
+		return flattenMachinePoolConfigsGcp(config.Spec.MachinePoolConfig), machinePoolStatusGcp, nil
+	case "vsphere":
+		config, err := c.GetCloudConfigVsphere(ctx, configUID)
+		if err != nil {
+			return nil, nil, err
+		} else if config == nil || config.Spec == nil {
+			return make([]interface{}, 0), nil, nil
+		}
+		return flattenMachinePoolConfigsVsphere(config.Spec.MachinePoolConfig), machinePoolStatusVsphere, nil
+	case "openstack":
+		config, err := c.GetCloudConfigOpenStack(ctx, configUID)
+		if err != nil {
+			return nil, nil, err
+		} else if config == nil || config.Spec == nil {
+			return make([]interface{}, 0), nil, nil
+		}
+		return flattenMachinePoolConfigsOpenStack(config.Spec.MachinePoolConfig), machinePoolStatusOpenStack, nil
+	case "eks":
+		config, err := c.GetCloudConfigEks(ctx, configUID)
+		if err != nil {
+			return nil, nil, err
+		} else if config == nil || config.Spec == nil {
+			return make([]interface{}, 0), nil, nil
+		}
+		return flattenMachinePoolConfigsEks(config.Spec.MachinePoolConfig), machinePoolStatusEks, nil
+	case "aks":
+		config, err := c.GetCloudConfigAks(ctx, configUID)
+		if err != nil {
+			return nil, nil, err
+		} else if config == nil || config.Spec == nil {
+			return make([]interface{}, 0), nil, nil
+		}
+		return flattenMachinePoolConfigsAks(config.Spec.MachinePoolConfig), machinePoolStatusAks, nil
+	}
+
+	// pools of other clouds, eg imported clusters, are not managed by us
+	return make([]interface{}, 0), nil, nil
+}
+
+func flattenMachinePoolSummaries(machinePools []interface{}) []interface{} {
+	summaries := make([]interface{}, 0, len(machinePools))
+	for _, mp := range machinePools {
+		machinePool := mp.(map[string]interface{})
+		summary := map[string]interface{}{
+			"name":        machinePool["name"],
+			"count":       machinePool["count"],
+			"ready_count": machinePool["ready_count"],
+		}
+		if controlPlane, ok := machinePool["control_plane"]; ok {
+			summary["control_plane"] = controlPlane
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
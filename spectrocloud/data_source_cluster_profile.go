@@ -2,7 +2,6 @@ package spectrocloud
 
 import (
 	"context"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/spectrocloud/hapi/models"
@@ -28,6 +27,12 @@ func dataSourceClusterProfile() *schema.Resource {
 				Computed:     true,
 				ExactlyOneOf: []string{"id", "name"},
 			},
+			"version": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"id"},
+			},
 			"pack": {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -43,6 +48,10 @@ func dataSourceClusterProfile() *schema.Resource {
 							Computed: true,
 							Optional: true,
 						},
+						"registry_uid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
 						"name": {
 							Type:     schema.TypeString,
 							Required: true,
@@ -61,14 +70,9 @@ func dataSourceClusterProfile() *schema.Resource {
 										Required: true,
 									},
 									"content": {
-										Type:     schema.TypeString,
-										Required: true,
-										DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-											if strings.TrimSpace(old) == strings.TrimSpace(new) {
-												return true
-											}
-											return false
-										},
+										Type:             schema.TypeString,
+										Required:         true,
+										DiffSuppressFunc: suppressYamlDiff,
 									},
 								},
 							},
@@ -78,15 +82,9 @@ func dataSourceClusterProfile() *schema.Resource {
 							Optional: true,
 						},
 						"values": {
-							Type:     schema.TypeString,
-							Optional: true,
-							DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-								// UI strips the trailing newline on save
-								if strings.TrimSpace(old) == strings.TrimSpace(new) {
-									return true
-								}
-								return false
-							},
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: suppressYamlDiff,
 						},
 					},
 				},
@@ -95,25 +93,24 @@ func dataSourceClusterProfile() *schema.Resource {
 	}
 }
 
-func dataSourceClusterProfileRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func dataSourceClusterProfileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
-	profiles, err := c.GetClusterProfiles()
+	profiles, err := c.GetClusterProfiles(ctx)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	var profile *models.V1ClusterProfile
+	if v, ok := d.GetOk("name"); ok {
+		profile = findClusterProfile(profiles, v.(string), d.Get("version").(string))
+	}
 	for _, p := range profiles {
-
 		if v, ok := d.GetOk("id"); ok && v.(string) == p.Metadata.UID {
 			profile = p
 			break
-		} else if v, ok := d.GetOk("name"); ok && v.(string) == p.Metadata.Name {
-			profile = p
-			break
 		}
 	}
 
@@ -128,26 +125,14 @@ func dataSourceClusterProfileRead(_ context.Context, d *schema.ResourceData, m i
 
 	d.SetId(profile.Metadata.UID)
 	d.Set("name", profile.Metadata.Name)
+	d.Set("version", profile.Spec.Version)
 	if profile.Spec.Published != nil && len(profile.Spec.Published.Packs) > 0 {
-		packManifests := make(map[string][]string)
-		for _, p := range profile.Spec.Published.Packs {
-			if len(p.Manifests) > 0 {
-				content, err := c.GetClusterProfileManifestPack(d.Id(), p.PackUID)
-				if err != nil {
-					return diag.FromErr(err)
-				}
-
-				if len(content) > 0 {
-					c := make([]string, len(content))
-					for i, co := range content {
-						c[i] = co.Spec.Published.Content
-					}
-					packManifests[p.PackUID] = c
-				}
-			}
+		packManifests, err := getPackManifests(ctx, c, d.Id(), profile.Spec.Published.Packs)
+		if err != nil {
+			return diag.FromErr(err)
 		}
 
-		packs := flattenPacks(profile.Spec.Published.Packs, packManifests)
+		packs := flattenPacks(profile.Spec.Published.Packs, packManifests, nil)
 		if err := d.Set("pack", packs); err != nil {
 			return diag.FromErr(err)
 		}
@@ -0,0 +1,177 @@
+package spectrocloud
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/spectrocloud/hapi/models"
+	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client"
+)
+
+func dataSourceClusters() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceClustersRead,
+
+		Schema: map[string]*schema.Schema{
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Set:      schema.HashString,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"cloud_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"clusters": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cloud_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cloud_config_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"health": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"kubernetes_version": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"tags": {
+							Type:     schema.TypeSet,
+							Computed: true,
+							Set:      schema.HashString,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"creation_timestamp": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceClustersRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*client.V1Client)
+
+	var diags diag.Diagnostics
+
+	if project, ok := d.GetOk("project"); ok {
+		projectUID, err := c.GetProjectUID(ctx, project.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		ctx = client.ContextWithProject(ctx, projectUID)
+	}
+
+	clusters, err := c.GetClusters(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+	tags := toTags(d)
+
+	summaries := make([]interface{}, 0)
+	uids := make([]string, 0)
+	for _, cluster := range clusters {
+		if v, ok := d.GetOk("cloud_type"); ok && cluster.Spec.CloudType != v.(string) {
+			continue
+		}
+		if v, ok := d.GetOk("status"); ok && clusterState(cluster) != v.(string) {
+			continue
+		}
+		if nameRegex != nil && !nameRegex.MatchString(cluster.Metadata.Name) {
+			continue
+		}
+		if !hasTags(cluster.Metadata.Labels, tags) {
+			continue
+		}
+
+		summaries = append(summaries, flattenClusterSummary(cluster))
+		uids = append(uids, cluster.Metadata.UID)
+	}
+
+	sort.Strings(uids)
+	d.SetId(strconv.Itoa(schema.HashString(strings.Join(uids, ","))))
+	if err := d.Set("clusters", summaries); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// hasTags reports whether the labels of a cluster contain all the given tags
+func hasTags(labels, tags map[string]string) bool {
+	for key, value := range tags {
+		if label, ok := labels[key]; !ok || label != value {
+			return false
+		}
+	}
+	return true
+}
+
+func flattenClusterSummary(cluster *models.V1SpectroCluster) map[string]interface{} {
+	summary := map[string]interface{}{
+		"id":                 cluster.Metadata.UID,
+		"name":               cluster.Metadata.Name,
+		"cloud_type":         cluster.Spec.CloudType,
+		"status":             clusterState(cluster),
+		"health":             clusterHealth(cluster),
+		"kubernetes_version": clusterKubernetesVersion(cluster),
+		"tags":               flattenTags(cluster.Metadata.Labels),
+		"creation_timestamp": clusterCreationTimestamp(cluster),
+	}
+	if cluster.Spec.CloudConfigRef != nil {
+		summary["cloud_config_id"] = cluster.Spec.CloudConfigRef.UID
+	}
+	return summary
+}
@@ -27,11 +27,11 @@ func dataSourceRegistryOci() *schema.Resource {
 	}
 }
 
-func dataSourceRegistryOciRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func dataSourceRegistryOciRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
 	if v, ok := d.GetOk("name"); ok {
-		registry, err := c.GetRegistryOciByName(v.(string))
+		registry, err := c.GetRegistryOciByName(ctx, v.(string))
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -58,7 +58,7 @@ func dataSourcePack() *schema.Resource {
 	}
 }
 
-func dataSourcePackRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func dataSourcePackRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	// Warning or errors can be collected in a slice type
@@ -85,7 +85,7 @@ func dataSourcePackRead(_ context.Context, d *schema.ResourceData, m interface{}
 		}
 	}
 
-	packs, err := c.GetPacks(filters)
+	packs, err := c.GetPacks(ctx, filters)
 	if err != nil {
 		return diag.FromErr(err)
 	}
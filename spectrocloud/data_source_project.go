@@ -29,11 +29,11 @@ func dataSourceProject() *schema.Resource {
 	}
 }
 
-func dataSourceProjectRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func dataSourceProjectRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
 	if v, ok := d.GetOk("name"); ok {
-		uid, err := c.GetProjectUID(v.(string))
+		uid, err := c.GetProjectUID(ctx, v.(string))
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -29,11 +29,11 @@ func dataSourceRole() *schema.Resource {
 	}
 }
 
-func dataSourceRoleRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func dataSourceRoleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
 	if v, ok := d.GetOk("name"); ok {
-		role, err := c.GetRole(v.(string))
+		role, err := c.GetRole(ctx, v.(string))
 		if err != nil {
 			return diag.FromErr(err)
 		}
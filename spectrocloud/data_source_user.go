@@ -29,11 +29,11 @@ func dataSourceUser() *schema.Resource {
 	}
 }
 
-func dataSourceUserRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func dataSourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
 	if v, ok := d.GetOk("name"); ok {
-		user, err := c.GetUser(v.(string))
+		user, err := c.GetUser(ctx, v.(string))
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -0,0 +1,95 @@
+package spectrocloud
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/spectrocloud/hapi/models"
+	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client"
+)
+
+// uidRegexp matches the object ids generated by Spectro Cloud
+var uidRegexp = regexp.MustCompile(`^[0-9a-f]{24}$`)
+
+// resolveImportID returns the uid for an import id given either as a uid or as a name
+func resolveImportID(id string, lookup func(name string) (string, error)) (string, error) {
+	if uidRegexp.MatchString(id) {
+		return id, nil
+	}
+	return lookup(id)
+}
+
+// resolveProjectImportID also accepts "project:name". Reads are always scoped
+// to the provider project, so the given project must be that same project.
+func resolveProjectImportID(ctx context.Context, c *client.V1Client, id string, lookup func(name string) (string, error)) (string, error) {
+	if i := strings.Index(id, ":"); i >= 0 {
+		project, name := id[:i], id[i+1:]
+		projectUID, err := c.GetProjectUID(ctx, project)
+		if err != nil {
+			return "", err
+		}
+		if projectUID != c.ProjectUID() {
+			return "", fmt.Errorf("'%s' belongs to project '%s', set project_name = \"%s\" in the provider to import it", name, project, project)
+		}
+		id = name
+	}
+	return resolveImportID(id, lookup)
+}
+
+// getImportCluster resolves the import id of a cluster resource and fetches the cluster
+func getImportCluster(ctx context.Context, c *client.V1Client, id string) (*models.V1SpectroCluster, error) {
+	uid, err := resolveProjectImportID(ctx, c, id, func(name string) (string, error) {
+		cluster, err := c.GetClusterByName(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		return cluster.Metadata.UID, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := c.GetCluster(ctx, uid)
+	if err != nil {
+		return nil, err
+	} else if cluster == nil {
+		return nil, fmt.Errorf("cluster '%s' not found", id)
+	}
+	return cluster, nil
+}
+
+// resourceClusterImporter returns an importer for the cluster resource of the given cloud type
+func resourceClusterImporter(cloudType string) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+		c := m.(*client.V1Client)
+
+		cluster, err := getImportCluster(ctx, c, d.Id())
+		if err != nil {
+			return nil, err
+		}
+		if cluster.Spec.CloudType != cloudType {
+			return nil, fmt.Errorf("cluster '%s' is a '%s' cluster and can not be imported as '%s'", d.Id(), cluster.Spec.CloudType, cloudType)
+		}
+
+		d.SetId(cluster.Metadata.UID)
+		if err := d.Set("error_grace_period_minutes", DefaultErrorGracePeriod); err != nil {
+			return nil, err
+		}
+		if err := d.Set("deletion_protection", false); err != nil {
+			return nil, err
+		}
+		if err := d.Set("force_delete", false); err != nil {
+			return nil, err
+		}
+		if err := d.Set("force_delete_delay", DefaultForceDeleteDelay); err != nil {
+			return nil, err
+		}
+		if err := d.Set("skip_backup_on_delete", true); err != nil {
+			return nil, err
+		}
+		return []*schema.ResourceData{d}, nil
+	}
+}
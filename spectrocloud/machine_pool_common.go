@@ -0,0 +1,354 @@
+package spectrocloud
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/spectrocloud/hapi/models"
+	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client"
+)
+
+// machinePoolAdapter binds the cloud agnostic machine pool reconciliation to
+// the schema and API of one cloud
+type machinePoolAdapter struct {
+	hash   schema.SchemaSetFunc
+	create func(ctx context.Context, c *client.V1Client, cloudConfigId string, machinePool map[string]interface{}) error
+	update func(ctx context.Context, c *client.V1Client, cloudConfigId string, oldMachinePool, machinePool map[string]interface{}) error
+	delete func(ctx context.Context, c *client.V1Client, cloudConfigId string, name string) error
+}
+
+type machinePoolAction string
+
+const (
+	machinePoolCreate machinePoolAction = "create"
+	machinePoolUpdate machinePoolAction = "update"
+	machinePoolDelete machinePoolAction = "delete"
+)
+
+type machinePoolChange struct {
+	action         machinePoolAction
+	name           string
+	oldMachinePool map[string]interface{}
+	machinePool    map[string]interface{}
+}
+
+// machinePoolList returns the machine pools of a TypeSet or TypeList attribute
+func machinePoolList(v interface{}) []interface{} {
+	switch machinePools := v.(type) {
+	case *schema.Set:
+		return machinePools.List()
+	case []interface{}:
+		return machinePools
+	}
+	return nil
+}
+
+// diffMachinePools returns the changes turning the old machine pools into the
+// new ones, in the order they have to be applied. Pools are matched by name, so
+// a renamed pool is deleted and created again. New pools are created before
+// old ones are deleted, the control plane is created first and deleted last.
+func diffMachinePools(oldMachinePools, newMachinePools []interface{}, hash schema.SchemaSetFunc) []machinePoolChange {
+	oldByName := make(map[string]map[string]interface{})
+	for _, mp := range oldMachinePools {
+		machinePool := mp.(map[string]interface{})
+		oldByName[machinePool["name"].(string)] = machinePool
+	}
+
+	creates := make([]machinePoolChange, 0)
+	updates := make([]machinePoolChange, 0)
+	for _, mp := range newMachinePools {
+		machinePool := mp.(map[string]interface{})
+		name := machinePool["name"].(string)
+
+		if oldMachinePool, ok := oldByName[name]; !ok {
+			creates = append(creates, machinePoolChange{action: machinePoolCreate, name: name, machinePool: machinePool})
+		} else {
+			if hash(oldMachinePool) != hash(machinePool) {
+				updates = append(updates, machinePoolChange{action: machinePoolUpdate, name: name, oldMachinePool: oldMachinePool, machinePool: machinePool})
+			}
+			// Processed
+			delete(oldByName, name)
+		}
+	}
+
+	deletes := make([]machinePoolChange, 0, len(oldByName))
+	for name, oldMachinePool := range oldByName {
+		deletes = append(deletes, machinePoolChange{action: machinePoolDelete, name: name, oldMachinePool: oldMachinePool})
+	}
+
+	sortMachinePoolChanges(creates, true)
+	sortMachinePoolChanges(updates, true)
+	sortMachinePoolChanges(deletes, false)
+
+	changes := append(creates, updates...)
+	return append(changes, deletes...)
+}
+
+// sortMachinePoolChanges orders changes by name, with the control plane first
+// or last
+func sortMachinePoolChanges(changes []machinePoolChange, controlPlaneFirst bool) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		ci, cj := isControlPlanePool(changes[i]), isControlPlanePool(changes[j])
+		if ci != cj {
+			return ci == controlPlaneFirst
+		}
+		return changes[i].name < changes[j].name
+	})
+}
+
+func isControlPlanePool(change machinePoolChange) bool {
+	machinePool := change.machinePool
+	if machinePool == nil {
+		machinePool = change.oldMachinePool
+	}
+	controlPlane, _ := machinePool["control_plane"].(bool)
+	return controlPlane
+}
+
+// updateMachinePools applies the machine pool changes of d through the adapter of the cloud
+func updateMachinePools(ctx context.Context, c *client.V1Client, d *schema.ResourceData, adapter machinePoolAdapter) error {
+	cloudConfigId := d.Get("cloud_config_id").(string)
+
+	oraw, nraw := d.GetChange("machine_pool")
+	for _, change := range diffMachinePools(machinePoolList(oraw), machinePoolList(nraw), adapter.hash) {
+		var err error
+		switch change.action {
+		case machinePoolCreate:
+			log.Printf("Create machine pool %s", change.name)
+			err = adapter.create(ctx, c, cloudConfigId, change.machinePool)
+		case machinePoolUpdate:
+			log.Printf("Change in machine pool %s", change.name)
+			err = adapter.update(ctx, c, cloudConfigId, change.oldMachinePool, keepAutoscaledSize(change.oldMachinePool, change.machinePool))
+		case machinePoolDelete:
+			log.Printf("Deleted machine pool %s", change.name)
+			err = adapter.delete(ctx, c, cloudConfigId, change.name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func schemaMachinePoolTaints() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"key": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"value": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"effect": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringInSlice([]string{"NoSchedule", "PreferNoSchedule", "NoExecute"}, false),
+				},
+			},
+		},
+	}
+}
+
+// schemaMachinePoolStringMap is the schema of the additional labels and
+// additional tags of a machine pool
+func schemaMachinePoolStringMap() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeMap,
+		Optional: true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+func toAdditionalNodePoolLabels(m map[string]interface{}) map[string]string {
+	labels, _ := m["additional_labels"].(map[string]interface{})
+	return expandStringMap(labels)
+}
+
+func toAdditionalNodePoolTags(m map[string]interface{}) map[string]string {
+	tags, _ := m["additional_tags"].(map[string]interface{})
+	return expandStringMap(tags)
+}
+
+func toNodePoolTaints(m map[string]interface{}) []*models.V1Taint {
+	taints := make([]*models.V1Taint, 0)
+	rawTaints, _ := m["taints"].([]interface{})
+	for _, t := range rawTaints {
+		taint := t.(map[string]interface{})
+		taints = append(taints, &models.V1Taint{
+			Key:    taint["key"].(string),
+			Value:  taint["value"].(string),
+			Effect: taint["effect"].(string),
+		})
+	}
+	return taints
+}
+
+// flattenNodePoolMetadata sets the labels, tags and taints of a flattened machine pool
+func flattenNodePoolMetadata(oi map[string]interface{}, labels, tags map[string]string, taints []*models.V1Taint) {
+	oi["additional_labels"] = labels
+	oi["additional_tags"] = tags
+
+	flatTaints := make([]interface{}, 0, len(taints))
+	for _, taint := range taints {
+		flatTaints = append(flatTaints, map[string]interface{}{
+			"key":    taint.Key,
+			"value":  taint.Value,
+			"effect": taint.Effect,
+		})
+	}
+	oi["taints"] = flatTaints
+}
+
+// hashNodePoolMetadata adds the labels, tags and taints of a machine pool to its hash
+func hashNodePoolMetadata(buf *bytes.Buffer, m map[string]interface{}) {
+	for _, key := range []string{"additional_labels", "additional_tags"} {
+		values, _ := m[key].(map[string]interface{})
+		names := make([]string, 0, len(values))
+		for name := range values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			buf.WriteString(fmt.Sprintf("%s:%s=%s-", key, name, values[name].(string)))
+		}
+	}
+
+	taints, _ := m["taints"].([]interface{})
+	for _, t := range taints {
+		taint := t.(map[string]interface{})
+		buf.WriteString(fmt.Sprintf("taint:%s=%s:%s-", taint["key"].(string), taint["value"].(string), taint["effect"].(string)))
+	}
+}
+
+// isAutoscaledPool reports whether the cluster autoscaler manages the size of
+// a machine pool, count is then only its initial size. The max bound alone
+// decides, min may be 0 for pools that scale to zero.
+func isAutoscaledPool(m map[string]interface{}) bool {
+	maxSize, _ := m["max"].(int)
+	return maxSize > 0
+}
+
+// keepAutoscaledSize returns an autoscaled machine pool with the size it has in
+// the state, as read from the API, so that updating it does not undo what the
+// autoscaler did
+func keepAutoscaledSize(oldMachinePool, machinePool map[string]interface{}) map[string]interface{} {
+	if !isAutoscaledPool(machinePool) || oldMachinePool == nil {
+		return machinePool
+	}
+
+	kept := make(map[string]interface{}, len(machinePool))
+	for k, v := range machinePool {
+		kept[k] = v
+	}
+	kept["count"] = oldMachinePool["count"]
+	return kept
+}
+
+// suppressAutoscaledCountDiff ignores the count of an existing autoscaled
+// machine pool, which only sets the size the pool is created with
+func suppressAutoscaledCountDiff(k, old, new string, d *schema.ResourceData) bool {
+	maxSize, _ := d.Get(strings.TrimSuffix(k, "count") + "max").(int)
+	return old != "" && maxSize > 0
+}
+
+// toMachinePoolSize returns the size and the autoscaler bounds of a machine
+// pool, autoscaled pools are sized within their bounds. Pools without
+// autoscaling are bound to their count.
+func toMachinePoolSize(m map[string]interface{}) (size, minSize, maxSize int32) {
+	count := int32(m["count"].(int))
+	if !isAutoscaledPool(m) {
+		return count, count, count
+	}
+
+	minSize, maxSize = int32(m["min"].(int)), int32(m["max"].(int))
+	size = count
+	if size < minSize {
+		size = minSize
+	} else if size > maxSize {
+		size = maxSize
+	}
+	return size, minSize, maxSize
+}
+
+// flattenMachinePoolSize sets the count and the autoscaler bounds of a
+// flattened machine pool
+func flattenMachinePoolSize(oi map[string]interface{}, size, minSize, maxSize int32) {
+	oi["count"] = int(size)
+	oi["min"] = int(minSize)
+	oi["max"] = int(maxSize)
+}
+
+// keepMachinePoolSizes reconciles the flattened sizes of machine pools with
+// the state. Pools without autoscaling are bound to their count by the API,
+// those bounds are not kept unless they differ.
+func keepMachinePoolSizes(d *schema.ResourceData, machinePools []interface{}) {
+	oldByName := make(map[string]map[string]interface{})
+	for _, mp := range machinePoolList(d.Get("machine_pool")) {
+		machinePool := mp.(map[string]interface{})
+		oldByName[machinePool["name"].(string)] = machinePool
+	}
+
+	for _, mp := range machinePools {
+		machinePool := mp.(map[string]interface{})
+		oldMachinePool, ok := oldByName[machinePool["name"].(string)]
+		if (!ok || !isAutoscaledPool(oldMachinePool)) && machinePool["min"] == machinePool["max"] {
+			machinePool["min"] = 0
+			machinePool["max"] = 0
+		}
+	}
+}
+
+// resourceMachinePoolSizesCustomizeDiff fails the plan when a machine pool has
+// no count without autoscaling, or autoscaler bounds without max or inverted
+func resourceMachinePoolSizesCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("machine_pool") {
+		return nil
+	}
+
+	for _, mp := range machinePoolList(d.Get("machine_pool")) {
+		machinePool := mp.(map[string]interface{})
+		name := machinePool["name"].(string)
+		count, _ := machinePool["count"].(int)
+		minSize, _ := machinePool["min"].(int)
+		maxSize, _ := machinePool["max"].(int)
+		if maxSize == 0 && minSize > 0 {
+			return fmt.Errorf("machine pool %s: min needs max to be set", name)
+		} else if maxSize == 0 && count == 0 {
+			return fmt.Errorf("machine pool %s: count has to be set unless max is set for the autoscaler", name)
+		} else if maxSize > 0 && minSize > maxSize {
+			return fmt.Errorf("machine pool %s: min %d is greater than max %d", name, minSize, maxSize)
+		}
+	}
+	return nil
+}
+
+// hashMachinePoolSize adds the count, or the autoscaler bounds of autoscaled
+// pools, to the hash of a machine pool
+func hashMachinePoolSize(buf *bytes.Buffer, m map[string]interface{}) {
+	if isAutoscaledPool(m) {
+		buf.WriteString(fmt.Sprintf("%d:%d-", m["min"].(int), m["max"].(int)))
+	} else {
+		buf.WriteString(fmt.Sprintf("%d-", m["count"].(int)))
+	}
+}
+
+func isMachinePoolSizeReady(m map[string]interface{}, machines int) bool {
+	if !isAutoscaledPool(m) {
+		return machines == m["count"].(int)
+	}
+	return machines >= m["min"].(int) && machines <= m["max"].(int)
+}
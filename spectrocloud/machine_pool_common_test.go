@@ -0,0 +1,161 @@
+package spectrocloud
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func testMachinePool(name string, controlPlane bool, count int) map[string]interface{} {
+	return map[string]interface{}{
+		"name":          name,
+		"control_plane": controlPlane,
+		"count":         count,
+	}
+}
+
+func testMachinePoolHash(v interface{}) int {
+	m := v.(map[string]interface{})
+	return schema.HashString(fmt.Sprintf("%s-%t-%d", m["name"], m["control_plane"], m["count"]))
+}
+
+func TestDiffMachinePools(t *testing.T) {
+	tests := []struct {
+		name string
+		old  []interface{}
+		new  []interface{}
+		want []string
+	}{
+		{
+			name: "unchanged",
+			old:  []interface{}{testMachinePool("cp", true, 1), testMachinePool("worker", false, 2)},
+			new:  []interface{}{testMachinePool("cp", true, 1), testMachinePool("worker", false, 2)},
+			want: []string{},
+		},
+		{
+			name: "create",
+			old:  []interface{}{testMachinePool("cp", true, 1)},
+			new:  []interface{}{testMachinePool("cp", true, 1), testMachinePool("worker", false, 2)},
+			want: []string{"create worker"},
+		},
+		{
+			name: "update",
+			old:  []interface{}{testMachinePool("cp", true, 1), testMachinePool("worker", false, 2)},
+			new:  []interface{}{testMachinePool("cp", true, 1), testMachinePool("worker", false, 3)},
+			want: []string{"update worker"},
+		},
+		{
+			name: "delete",
+			old:  []interface{}{testMachinePool("cp", true, 1), testMachinePool("worker", false, 2)},
+			new:  []interface{}{testMachinePool("cp", true, 1)},
+			want: []string{"delete worker"},
+		},
+		{
+			name: "rename is delete and create",
+			old:  []interface{}{testMachinePool("cp", true, 1), testMachinePool("worker", false, 2)},
+			new:  []interface{}{testMachinePool("cp", true, 1), testMachinePool("worker-2", false, 2)},
+			want: []string{"create worker-2", "delete worker"},
+		},
+		{
+			name: "control plane created first",
+			old:  []interface{}{},
+			new:  []interface{}{testMachinePool("worker-b", false, 1), testMachinePool("worker-a", false, 1), testMachinePool("cp", true, 3)},
+			want: []string{"create cp", "create worker-a", "create worker-b"},
+		},
+		{
+			name: "control plane updated first",
+			old:  []interface{}{testMachinePool("worker", false, 1), testMachinePool("cp", true, 1)},
+			new:  []interface{}{testMachinePool("worker", false, 2), testMachinePool("cp", true, 3)},
+			want: []string{"update cp", "update worker"},
+		},
+		{
+			name: "control plane deleted last",
+			old:  []interface{}{testMachinePool("cp", true, 1), testMachinePool("worker-a", false, 1), testMachinePool("worker-b", false, 1)},
+			new:  []interface{}{},
+			want: []string{"delete worker-a", "delete worker-b", "delete cp"},
+		},
+		{
+			name: "creates before updates before deletes",
+			old:  []interface{}{testMachinePool("cp", true, 1), testMachinePool("old", false, 1), testMachinePool("worker", false, 1)},
+			new:  []interface{}{testMachinePool("cp", true, 1), testMachinePool("new", false, 1), testMachinePool("worker", false, 2)},
+			want: []string{"create new", "update worker", "delete old"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, change := range diffMachinePools(tt.old, tt.new, testMachinePoolHash) {
+				got = append(got, fmt.Sprintf("%s %s", change.action, change.name))
+
+				if change.action != machinePoolCreate && change.oldMachinePool == nil {
+					t.Errorf("%s %s has no old machine pool", change.action, change.name)
+				}
+				if change.action != machinePoolDelete && change.machinePool == nil {
+					t.Errorf("%s %s has no machine pool", change.action, change.name)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got changes %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func testAutoscaledPool(name string, count, minSize, maxSize int) map[string]interface{} {
+	machinePool := testMachinePool(name, false, count)
+	machinePool["min"] = minSize
+	machinePool["max"] = maxSize
+	return machinePool
+}
+
+func TestToMachinePoolSize(t *testing.T) {
+	tests := []struct {
+		name        string
+		machinePool map[string]interface{}
+		want        [3]int32
+	}{
+		{"fixed", testMachinePool("worker", false, 3), [3]int32{3, 3, 3}},
+		{"autoscaled", testAutoscaledPool("worker", 3, 1, 5), [3]int32{3, 1, 5}},
+		{"autoscaled below min", testAutoscaledPool("worker", 0, 2, 5), [3]int32{2, 2, 5}},
+		{"autoscaled above max", testAutoscaledPool("worker", 8, 1, 5), [3]int32{5, 1, 5}},
+		{"scale to zero", testAutoscaledPool("worker", 0, 0, 5), [3]int32{0, 0, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size, minSize, maxSize := toMachinePoolSize(tt.machinePool)
+			if got := [3]int32{size, minSize, maxSize}; got != tt.want {
+				t.Errorf("got size, min and max %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeepAutoscaledSize(t *testing.T) {
+	tests := []struct {
+		name           string
+		oldMachinePool map[string]interface{}
+		machinePool    map[string]interface{}
+		wantCount      int
+	}{
+		{"fixed pool is resized", testMachinePool("worker", false, 2), testMachinePool("worker", false, 4), 4},
+		{"autoscaled pool keeps its size", testAutoscaledPool("worker", 4, 1, 5), testAutoscaledPool("worker", 1, 1, 5), 4},
+		{"pool becoming autoscaled keeps its size", testMachinePool("worker", false, 3), testAutoscaledPool("worker", 1, 0, 5), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configured := tt.machinePool["count"]
+			got := keepAutoscaledSize(tt.oldMachinePool, tt.machinePool)
+			if got["count"] != tt.wantCount {
+				t.Errorf("got count %v, want %d", got["count"], tt.wantCount)
+			}
+			if tt.machinePool["count"] != configured {
+				t.Errorf("the configured machine pool was changed")
+			}
+		})
+	}
+}
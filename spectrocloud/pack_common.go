@@ -0,0 +1,357 @@
+package spectrocloud
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/spectrocloud/hapi/models"
+	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client"
+)
+
+// resourceClusterPacksCustomizeDiff fails the plan of a cluster of the given
+// cloud type when one of its pack overrides does not exist
+func resourceClusterPacksCustomizeDiff(cloudType string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		c := m.(*client.V1Client)
+
+		if err := validatePackOverrides(ctx, c, d, "pack", "cluster_profile_id", cloudType); err != nil {
+			return err
+		}
+		for i := range d.Get("cluster_profile").([]interface{}) {
+			key, profileKey := fmt.Sprintf("cluster_profile.%d.pack", i), fmt.Sprintf("cluster_profile.%d.id", i)
+			if err := validatePackOverrides(ctx, c, d, key, profileKey, cloudType); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// resourceCloudPacksCustomizeDiff validates the packs of a cluster profile
+// against its cloud attribute
+func resourceCloudPacksCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("cloud") {
+		return nil
+	}
+
+	c := m.(*client.V1Client)
+	return validatePacks(ctx, c, d, "pack", d.Get("cloud").(string))
+}
+
+// resourceClusterImportPacksCustomizeDiff validates the pack overrides of an
+// imported cluster against its cloud attribute
+func resourceClusterImportPacksCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("cloud") {
+		return nil
+	}
+
+	c := m.(*client.V1Client)
+	return validatePackOverrides(ctx, c, d, "pack", "cluster_profile_id", d.Get("cloud").(string))
+}
+
+// validatePacks checks that every spectro pack of the list at key exists with
+// its tag in its registry, or in any registry when none is set, and supports
+// cloudType. Packs are only validated when they change, and once their name and
+// tag are known.
+func validatePacks(ctx context.Context, c *client.V1Client, d *schema.ResourceDiff, key, cloudType string) error {
+	if d.Id() != "" && !d.HasChange(key) {
+		return nil
+	}
+
+	packs, _ := d.Get(key).([]interface{})
+	for i, p := range packs {
+		pack := p.(map[string]interface{})
+		if packType, _ := pack["type"].(string); packType != "" && packType != string(models.V1PackTypeSpectro) {
+			continue
+		}
+		if !d.NewValueKnown(fmt.Sprintf("%s.%d.name", key, i)) || !d.NewValueKnown(fmt.Sprintf("%s.%d.tag", key, i)) {
+			continue
+		}
+
+		name, tag := pack["name"].(string), pack["tag"].(string)
+		registryUID, _ := pack["registry_uid"].(string)
+		if !d.NewValueKnown(fmt.Sprintf("%s.%d.registry_uid", key, i)) {
+			registryUID = ""
+		}
+		if err := validatePack(ctx, c, name, tag, registryUID, cloudType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePackOverrides checks the pack overrides at key against the layers of
+// the cluster profile at profileKey. Overrides have no type of their own, so
+// manifest and helm layers are skipped and spectro layers have to exist with
+// their tag in the registry of the profile. Nothing is validated until the
+// profile is known.
+func validatePackOverrides(ctx context.Context, c *client.V1Client, d *schema.ResourceDiff, key, profileKey, cloudType string) error {
+	if d.Id() != "" && !d.HasChange(key) && !d.HasChange(profileKey) {
+		return nil
+	}
+
+	packs, _ := d.Get(key).([]interface{})
+	if len(packs) == 0 || !d.NewValueKnown(profileKey) {
+		return nil
+	}
+	profileUID, _ := d.Get(profileKey).(string)
+	if profileUID == "" {
+		return nil
+	}
+
+	profile, err := c.GetClusterProfile(ctx, profileUID)
+	if err != nil {
+		return err
+	} else if profile == nil || profile.Spec == nil || profile.Spec.Published == nil {
+		return fmt.Errorf("cluster profile '%s' not found", profileUID)
+	}
+	layers := make(map[string]*models.V1PackRef)
+	for _, layer := range profile.Spec.Published.Packs {
+		if layer.Name != nil {
+			layers[*layer.Name] = layer
+		}
+	}
+
+	for i, p := range packs {
+		pack := p.(map[string]interface{})
+		if !d.NewValueKnown(fmt.Sprintf("%s.%d.name", key, i)) || !d.NewValueKnown(fmt.Sprintf("%s.%d.tag", key, i)) {
+			continue
+		}
+
+		name, tag := pack["name"].(string), pack["tag"].(string)
+		layer, found := layers[name]
+		if !found {
+			return fmt.Errorf("pack '%s' is not a layer of cluster profile '%s'", name, profileUID)
+		} else if layer.Type != "" && layer.Type != models.V1PackTypeSpectro {
+			continue
+		}
+		if err := validatePack(ctx, c, name, tag, layer.RegistryUID, cloudType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePack(ctx context.Context, c *client.V1Client, name, tag, registryUID, cloudType string) error {
+	filters := []string{fmt.Sprintf("spec.name=%s", name)}
+	if cloudType != "" && cloudType != "all" {
+		filters = append(filters, fmt.Sprintf("spec.cloudTypes_in_%s,all", cloudType))
+	}
+	if registryUID != "" {
+		filters = append(filters, fmt.Sprintf("spec.registryUid=%s", registryUID))
+	}
+
+	packs, err := c.GetPacks(ctx, filters)
+	if err != nil {
+		return err
+	} else if len(packs) == 0 && registryUID != "" {
+		return fmt.Errorf("pack '%s' not found in registry '%s' for cloud '%s'", name, registryUID, cloudType)
+	} else if len(packs) == 0 {
+		return fmt.Errorf("pack '%s' not found in any registry for cloud '%s'", name, cloudType)
+	}
+
+	tags := make([]string, 0, len(packs))
+	for _, pack := range packs {
+		if tag == "" || packTagMatches(tag, pack.Spec.Version) {
+			return nil
+		}
+		tags = append(tags, pack.Spec.Version)
+	}
+
+	sort.Strings(tags)
+	return fmt.Errorf("pack '%s' has no tag '%s' for cloud '%s', available tags: %s", name, tag, cloudType, strings.Join(tags, ", "))
+}
+
+// packTagMatches reports whether tag selects version, tags may end in x
+// wildcards as in 1.18.x
+func packTagMatches(tag, version string) bool {
+	if tag == version {
+		return true
+	}
+
+	tagParts, versionParts := strings.Split(tag, "."), strings.Split(version, ".")
+	if len(tagParts) != len(versionParts) {
+		return false
+	}
+	for i, part := range tagParts {
+		if part != "x" && part != versionParts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// getPackManifests returns the content of the manifests of the packs of a
+// cluster profile, by pack uid and manifest name
+func getPackManifests(ctx context.Context, c *client.V1Client, profileUID string, packs []*models.V1PackRef) (map[string]map[string]string, error) {
+	packManifests := make(map[string]map[string]string)
+	for _, p := range packs {
+		if len(p.Manifests) == 0 {
+			continue
+		}
+
+		manifests, err := c.GetClusterProfileManifestPack(ctx, profileUID, p.PackUID)
+		if err != nil {
+			return nil, err
+		}
+
+		content := make(map[string]string, len(manifests))
+		for _, manifest := range manifests {
+			if manifest.Metadata == nil || manifest.Spec == nil || manifest.Spec.Published == nil {
+				continue
+			}
+			content[manifest.Metadata.Name] = manifest.Spec.Published.Content
+		}
+		packManifests[p.PackUID] = content
+	}
+	return packManifests, nil
+}
+
+// sortManifestRefs orders the manifests of a pack as in the previous manifests,
+// manifests that were not there before follow sorted by name
+func sortManifestRefs(manifests []*models.V1ManifestRef, oldManifests []interface{}) []*models.V1ManifestRef {
+	positions := make(map[string]int, len(oldManifests))
+	for i, m := range oldManifests {
+		positions[m.(map[string]interface{})["name"].(string)] = i
+	}
+
+	sorted := make([]*models.V1ManifestRef, len(manifests))
+	copy(sorted, manifests)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		pi, oki := positions[sorted[i].Name]
+		pj, okj := positions[sorted[j].Name]
+		if oki != okj {
+			return oki
+		} else if oki {
+			return pi < pj
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// toManifestContent returns the content of a manifest, loaded from a file or
+// from the YAML files of a directory, or else given inline
+func toManifestContent(m map[string]interface{}) (string, error) {
+	name, _ := m["name"].(string)
+	content, _ := m["content"].(string)
+	file, _ := m["file"].(string)
+	directory, _ := m["directory"].(string)
+
+	sources := 0
+	for _, source := range []string{strings.TrimSpace(content), file, directory} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources == 0 {
+		return "", fmt.Errorf("manifest %s needs one of content, file or directory", name)
+	} else if sources > 1 {
+		return "", fmt.Errorf("manifest %s can only set one of content, file or directory", name)
+	}
+
+	switch {
+	case file != "":
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("manifest %s: %v", name, err)
+		}
+		content = string(data)
+	case directory != "":
+		files, err := ioutil.ReadDir(directory)
+		if err != nil {
+			return "", fmt.Errorf("manifest %s: %v", name, err)
+		}
+
+		// ReadDir sorts by file name, documents keep that order
+		documents := make([]string, 0, len(files))
+		for _, f := range files {
+			if ext := filepath.Ext(f.Name()); f.IsDir() || (ext != ".yaml" && ext != ".yml") {
+				continue
+			}
+			data, err := ioutil.ReadFile(filepath.Join(directory, f.Name()))
+			if err != nil {
+				return "", fmt.Errorf("manifest %s: %v", name, err)
+			}
+			documents = append(documents, strings.TrimSpace(string(data)))
+		}
+		if len(documents) == 0 {
+			return "", fmt.Errorf("manifest %s: no YAML files in directory %s", name, directory)
+		}
+		content = strings.Join(documents, "\n---\n")
+	}
+
+	// UI strips a single newline, so we should do the same
+	return strings.TrimSpace(content), nil
+}
+
+func hashManifestContent(content string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(content)))
+	return hex.EncodeToString(sum[:])
+}
+
+// manifestFilesChanged reports whether a manifest loaded from a file or
+// directory no longer matches the hash of the content in the profile
+func manifestFilesChanged(d *schema.ResourceDiff) bool {
+	oldPacks, _ := d.GetChange("pack")
+	hashes := make(map[string]string)
+	for _, p := range oldPacks.([]interface{}) {
+		pack := p.(map[string]interface{})
+		manifests, _ := pack["manifest"].([]interface{})
+		for _, m := range manifests {
+			manifest := m.(map[string]interface{})
+			hashes[pack["name"].(string)+"/"+manifest["name"].(string)], _ = manifest["content_hash"].(string)
+		}
+	}
+
+	for i, p := range d.Get("pack").([]interface{}) {
+		pack := p.(map[string]interface{})
+		manifests, _ := pack["manifest"].([]interface{})
+		for j, m := range manifests {
+			key := fmt.Sprintf("pack.%d.manifest.%d", i, j)
+			if !d.NewValueKnown(fmt.Sprintf("pack.%d.name", i)) || !d.NewValueKnown(key+".name") ||
+				!d.NewValueKnown(key+".file") || !d.NewValueKnown(key+".directory") {
+				continue
+			}
+
+			// manifests without a hash yet show up in the diff of the pack
+			manifest := m.(map[string]interface{})
+			file, _ := manifest["file"].(string)
+			directory, _ := manifest["directory"].(string)
+			hash := hashes[pack["name"].(string)+"/"+manifest["name"].(string)]
+			if hash == "" || (file == "" && directory == "") {
+				continue
+			}
+			if content, err := toManifestContent(manifest); err == nil && hashManifestContent(content) != hash {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resourceManifestsCustomizeDiff fails the plan when a manifest of a pack has
+// no content, file or directory, or when its file or directory can not be read
+func resourceManifestsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	for i, p := range d.Get("pack").([]interface{}) {
+		pack := p.(map[string]interface{})
+		manifests, _ := pack["manifest"].([]interface{})
+		for j, manifest := range manifests {
+			key := fmt.Sprintf("pack.%d.manifest.%d", i, j)
+			if !d.NewValueKnown(key+".content") || !d.NewValueKnown(key+".file") || !d.NewValueKnown(key+".directory") {
+				continue
+			}
+			if _, err := toManifestContent(manifest.(map[string]interface{})); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
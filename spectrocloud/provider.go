@@ -4,11 +4,14 @@ import (
 	"context"
 	"crypto/tls"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func New(_ string) func() *schema.Provider {
@@ -22,15 +25,21 @@ func New(_ string) func() *schema.Provider {
 				},
 				"username": &schema.Schema{
 					Type:        schema.TypeString,
-					Required:    true,
+					Optional:    true,
 					DefaultFunc: schema.EnvDefaultFunc("SPECTROCLOUD_USERNAME", nil),
 				},
 				"password": &schema.Schema{
 					Type:        schema.TypeString,
-					Required:    true,
+					Optional:    true,
 					Sensitive:   true,
 					DefaultFunc: schema.EnvDefaultFunc("SPECTROCLOUD_PASSWORD", nil),
 				},
+				"api_key": &schema.Schema{
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					DefaultFunc: schema.EnvDefaultFunc("SPECTROCLOUD_APIKEY", nil),
+				},
 				"project_name": &schema.Schema{
 					Type:     schema.TypeString,
 					Optional: true,
@@ -39,6 +48,34 @@ func New(_ string) func() *schema.Provider {
 					Type:     schema.TypeBool,
 					Optional: true,
 				},
+				"retry_max_attempts": &schema.Schema{
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      3,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"retry_wait_min": &schema.Schema{
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      1,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"retry_wait_max": &schema.Schema{
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      30,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"max_requests_per_second": &schema.Schema{
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"http_trace_file": &schema.Schema{
+					Type:     schema.TypeString,
+					Optional: true,
+				},
 			},
 			ResourcesMap: map[string]*schema.Resource{
 				"spectrocloud_team": resourceTeam(),
@@ -63,7 +100,8 @@ func New(_ string) func() *schema.Provider {
 				"spectrocloud_cloudaccount_openstack": resourceCloudAccountOpenstack(),
 				"spectrocloud_cluster_openstack":      resourceClusterOpenStack(),
 
-				"spectrocloud_cluster_vsphere": resourceClusterVsphere(),
+				"spectrocloud_cloudaccount_vsphere": resourceCloudAccountVsphere(),
+				"spectrocloud_cluster_vsphere":      resourceClusterVsphere(),
 
 				"spectrocloud_cluster_import": resourceClusterImport(),
 
@@ -81,6 +119,8 @@ func New(_ string) func() *schema.Provider {
 				"spectrocloud_pack": dataSourcePack(),
 
 				"spectrocloud_cluster_profile": dataSourceClusterProfile(),
+				"spectrocloud_cluster":         dataSourceCluster(),
+				"spectrocloud_clusters":        dataSourceClusters(),
 
 				"spectrocloud_cloudaccount_aws":     dataSourceCloudAccountAws(),
 				"spectrocloud_cloudaccount_azure":   dataSourceCloudAccountAzure(),
@@ -103,19 +143,42 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	host := d.Get("host").(string)
 	username := d.Get("username").(string)
 	password := d.Get("password").(string)
+	apiKey := d.Get("api_key").(string)
 	projectName := d.Get("project_name").(string)
 	ignoreTlsError := d.Get("ignore_insecure_tls_error").(bool)
+	retryPolicy := client.RetryPolicy{
+		MaxAttempts: d.Get("retry_max_attempts").(int),
+		WaitMin:     time.Duration(d.Get("retry_wait_min").(int)) * time.Second,
+		WaitMax:     time.Duration(d.Get("retry_wait_max").(int)) * time.Second,
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
-	if (username == "") || (password == "") {
+	if apiKey != "" && (username != "" || password != "") {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create Spectro Cloud client",
+			Detail:   "Only one of api_key or username/password can be specified",
+		})
+		return nil, diags
+	}
+
+	if apiKey == "" && ((username == "") || (password == "")) {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Unable to create Spectro Cloud client",
-			Detail:   "Unable to authenticate user for authenticated Spectro Cloud client",
+			Detail:   "Either api_key or both username and password must be specified",
+		})
+		return nil, diags
+	}
+
+	if retryPolicy.WaitMin > retryPolicy.WaitMax {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Invalid retry configuration",
+			Detail:   "retry_wait_min must not be greater than retry_wait_max",
 		})
-		// TODO(saamalik) verify this block "can" happen (e.g: does required guard this?)
 		return nil, diags
 	}
 
@@ -123,15 +186,30 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 	}
 
-	c := client.New(host, username, password, "")
+	opts := []client.Option{
+		client.WithRetryPolicy(retryPolicy),
+		client.WithRateLimit(d.Get("max_requests_per_second").(int)),
+	}
+	if traceFile := d.Get("http_trace_file").(string); traceFile != "" {
+		// fail early when the trace can not be written
+		f, err := os.OpenFile(traceFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		if err := f.Close(); err != nil {
+			return nil, diag.FromErr(err)
+		}
+		opts = append(opts, client.WithHttpTraceFile(traceFile))
+	}
+	c := client.New(host, username, password, apiKey, "", opts...)
 
 	if projectName != "" {
-		uid, err := c.GetProjectUID(projectName)
+		uid, err := c.GetProjectUID(ctx, projectName)
 		if err != nil {
 			return nil, diag.FromErr(err)
 		}
 
-		c = client.New(host, username, password, uid)
+		c = client.New(host, username, password, apiKey, uid, opts...)
 	}
 
 	return c, diags
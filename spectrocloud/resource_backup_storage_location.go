@@ -2,6 +2,7 @@ package spectrocloud
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -19,6 +20,9 @@ func resourceBackupStorageLocation() *schema.Resource {
 		ReadContext:   resourceBackupStorageLocationRead,
 		UpdateContext: resourceBackupStorageLocationUpdate,
 		DeleteContext: resourceBackupStorageLocationDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceBackupStorageLocationImport,
+		},
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(10 * time.Minute),
@@ -96,7 +100,7 @@ func resourceBackupStorageLocationCreate(ctx context.Context, d *schema.Resource
 	var diags diag.Diagnostics
 
 	bsl := toBackupStorageLocation(d)
-	uid, err := c.CreateS3BackupStorageLocation(bsl)
+	uid, err := c.CreateS3BackupStorageLocation(ctx, bsl)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -109,7 +113,7 @@ func resourceBackupStorageLocationRead(ctx context.Context, d *schema.ResourceDa
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
 
-	bsl, err := c.GetBackupStorageLocation(d.Id())
+	bsl, err := c.GetBackupStorageLocation(ctx, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	} else if bsl == nil {
@@ -126,7 +130,7 @@ func resourceBackupStorageLocationRead(ctx context.Context, d *schema.ResourceDa
 	}
 
 	if bsl.Spec.Storage == "s3" {
-		s3Bsl, err := c.GetS3BackupStorageLocation(d.Id())
+		s3Bsl, err := c.GetS3BackupStorageLocation(ctx, d.Id())
 		if err != nil {
 			return diag.FromErr(err)
 		} else if s3Bsl == nil {
@@ -174,12 +178,35 @@ func resourceBackupStorageLocationRead(ctx context.Context, d *schema.ResourceDa
 	return diags
 }
 
+func resourceBackupStorageLocationImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*client.V1Client)
+
+	uid, err := resolveProjectImportID(ctx, c, d.Id(), func(name string) (string, error) {
+		bsls, err := c.ListBackupStorageLocation(ctx, true)
+		if err != nil {
+			return "", err
+		}
+		for _, bsl := range bsls {
+			if bsl.Metadata.Name == name {
+				return bsl.Metadata.UID, nil
+			}
+		}
+		return "", fmt.Errorf("backup storage location '%s' not found", name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(uid)
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceBackupStorageLocationUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
 
 	bsl := toBackupStorageLocation(d)
-	err := c.UpdateS3BackupStorageLocation(d.Id(), bsl)
+	err := c.UpdateS3BackupStorageLocation(ctx, d.Id(), bsl)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -190,7 +217,7 @@ func resourceBackupStorageLocationUpdate(ctx context.Context, d *schema.Resource
 func resourceBackupStorageLocationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
-	err := c.DeleteS3BackupStorageLocation(d.Id())
+	err := c.DeleteS3BackupStorageLocation(ctx, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -20,6 +20,9 @@ func resourceCloudAccountAws() *schema.Resource {
 		ReadContext:   resourceCloudAccountAwsRead,
 		UpdateContext: resourceCloudAccountAwsUpdate,
 		DeleteContext: resourceCloudAccountAwsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCloudAccountAwsImport,
+		},
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -61,7 +64,7 @@ func resourceCloudAccountAwsCreate(ctx context.Context, d *schema.ResourceData,
 
 	account := toAwsAccount(d)
 
-	uid, err := c.CreateCloudAccountAws(account)
+	uid, err := c.CreateCloudAccountAws(ctx, account)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -73,14 +76,14 @@ func resourceCloudAccountAwsCreate(ctx context.Context, d *schema.ResourceData,
 	return diags
 }
 
-func resourceCloudAccountAwsRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceCloudAccountAwsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 
 	uid := d.Id()
 
-	account, err := c.GetCloudAccountAws(uid)
+	account, err := c.GetCloudAccountAws(ctx, uid)
 	if err != nil {
 		return diag.FromErr(err)
 	} else if account == nil {
@@ -109,6 +112,29 @@ func resourceCloudAccountAwsRead(_ context.Context, d *schema.ResourceData, m in
 	return diags
 }
 
+func resourceCloudAccountAwsImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*client.V1Client)
+
+	uid, err := resolveProjectImportID(ctx, c, d.Id(), func(name string) (string, error) {
+		accounts, err := c.GetCloudAccountsAws(ctx)
+		if err != nil {
+			return "", err
+		}
+		for _, account := range accounts {
+			if account.Metadata.Name == name {
+				return account.Metadata.UID, nil
+			}
+		}
+		return "", fmt.Errorf("aws cloud account '%s' not found", name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(uid)
+	return []*schema.ResourceData{d}, nil
+}
+
 //
 func resourceCloudAccountAwsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
@@ -118,7 +144,7 @@ func resourceCloudAccountAwsUpdate(ctx context.Context, d *schema.ResourceData,
 
 	account := toAwsAccount(d)
 
-	err := c.UpdateCloudAccountAws(account)
+	err := c.UpdateCloudAccountAws(ctx, account)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -128,14 +154,14 @@ func resourceCloudAccountAwsUpdate(ctx context.Context, d *schema.ResourceData,
 	return diags
 }
 
-func resourceCloudAccountAwsDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceCloudAccountAwsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 
 	cloudAccountID := d.Id()
 
-	err := c.DeleteCloudAccountAws(cloudAccountID)
+	err := c.DeleteCloudAccountAws(ctx, cloudAccountID)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -2,6 +2,7 @@ package spectrocloud
 
 import (
 	"context"
+	"fmt"
 	"github.com/go-openapi/strfmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -16,6 +17,9 @@ func resourceCloudAccountAzure() *schema.Resource {
 		ReadContext:   resourceCloudAccountAzureRead,
 		UpdateContext: resourceCloudAccountAzureUpdate,
 		DeleteContext: resourceCloudAccountAzureDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCloudAccountAzureImport,
+		},
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -52,7 +56,7 @@ func resourceCloudAccountAzureCreate(ctx context.Context, d *schema.ResourceData
 
 	account := toAzureAccount(d)
 
-	uid, err := c.CreateCloudAccountAzure(account)
+	uid, err := c.CreateCloudAccountAzure(ctx, account)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -64,14 +68,14 @@ func resourceCloudAccountAzureCreate(ctx context.Context, d *schema.ResourceData
 	return diags
 }
 
-func resourceCloudAccountAzureRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceCloudAccountAzureRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 
 	uid := d.Id()
 
-	account, err := c.GetCloudAccountAzure(uid)
+	account, err := c.GetCloudAccountAzure(ctx, uid)
 	if err != nil {
 		return diag.FromErr(err)
 	} else if account == nil {
@@ -93,6 +97,29 @@ func resourceCloudAccountAzureRead(_ context.Context, d *schema.ResourceData, m
 	return diags
 }
 
+func resourceCloudAccountAzureImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*client.V1Client)
+
+	uid, err := resolveProjectImportID(ctx, c, d.Id(), func(name string) (string, error) {
+		accounts, err := c.GetCloudAccountsAzure(ctx)
+		if err != nil {
+			return "", err
+		}
+		for _, account := range accounts {
+			if account.Metadata.Name == name {
+				return account.Metadata.UID, nil
+			}
+		}
+		return "", fmt.Errorf("azure cloud account '%s' not found", name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(uid)
+	return []*schema.ResourceData{d}, nil
+}
+
 //
 func resourceCloudAccountAzureUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
@@ -102,7 +129,7 @@ func resourceCloudAccountAzureUpdate(ctx context.Context, d *schema.ResourceData
 
 	account := toAzureAccount(d)
 
-	err := c.UpdateCloudAccountAzure(account)
+	err := c.UpdateCloudAccountAzure(ctx, account)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -112,14 +139,14 @@ func resourceCloudAccountAzureUpdate(ctx context.Context, d *schema.ResourceData
 	return diags
 }
 
-func resourceCloudAccountAzureDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceCloudAccountAzureDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 
 	cloudAccountID := d.Id()
 
-	err := c.DeleteCloudAccountAzure(cloudAccountID)
+	err := c.DeleteCloudAccountAzure(ctx, cloudAccountID)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -2,6 +2,7 @@ package spectrocloud
 
 import (
 	"context"
+	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/spectrocloud/hapi/models"
@@ -14,6 +15,9 @@ func resourceCloudAccountGcp() *schema.Resource {
 		ReadContext:   resourceCloudAccountGcpRead,
 		UpdateContext: resourceCloudAccountGcpUpdate,
 		DeleteContext: resourceCloudAccountGcpDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCloudAccountGcpImport,
+		},
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -36,7 +40,7 @@ func resourceCloudAccountGcpCreate(ctx context.Context, d *schema.ResourceData,
 
 	account := toGcpAccount(d)
 
-	uid, err := c.CreateCloudAccountGcp(account)
+	uid, err := c.CreateCloudAccountGcp(ctx, account)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -48,14 +52,14 @@ func resourceCloudAccountGcpCreate(ctx context.Context, d *schema.ResourceData,
 	return diags
 }
 
-func resourceCloudAccountGcpRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceCloudAccountGcpRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 
 	uid := d.Id()
 
-	account, err := c.GetCloudAccountGcp(uid)
+	account, err := c.GetCloudAccountGcp(ctx, uid)
 	if err != nil {
 		return diag.FromErr(err)
 	} else if account == nil {
@@ -71,6 +75,29 @@ func resourceCloudAccountGcpRead(_ context.Context, d *schema.ResourceData, m in
 	return diags
 }
 
+func resourceCloudAccountGcpImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*client.V1Client)
+
+	uid, err := resolveProjectImportID(ctx, c, d.Id(), func(name string) (string, error) {
+		accounts, err := c.GetCloudAccountsGcp(ctx)
+		if err != nil {
+			return "", err
+		}
+		for _, account := range accounts {
+			if account.Metadata.Name == name {
+				return account.Metadata.UID, nil
+			}
+		}
+		return "", fmt.Errorf("gcp cloud account '%s' not found", name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(uid)
+	return []*schema.ResourceData{d}, nil
+}
+
 //
 func resourceCloudAccountGcpUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
@@ -80,7 +107,7 @@ func resourceCloudAccountGcpUpdate(ctx context.Context, d *schema.ResourceData,
 
 	account := toGcpAccount(d)
 
-	err := c.UpdateCloudAccountGcp(account)
+	err := c.UpdateCloudAccountGcp(ctx, account)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -90,14 +117,14 @@ func resourceCloudAccountGcpUpdate(ctx context.Context, d *schema.ResourceData,
 	return diags
 }
 
-func resourceCloudAccountGcpDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceCloudAccountGcpDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 
 	cloudAccountID := d.Id()
 
-	err := c.DeleteCloudAccountGcp(cloudAccountID)
+	err := c.DeleteCloudAccountGcp(ctx, cloudAccountID)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -2,6 +2,7 @@ package spectrocloud
 
 import (
 	"context"
+	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/spectrocloud/gomi/pkg/ptr"
@@ -15,6 +16,9 @@ func resourceCloudAccountOpenstack() *schema.Resource {
 		ReadContext:   resourceCloudAccountOpenStackRead,
 		UpdateContext: resourceCloudAccountOpenStackUpdate,
 		DeleteContext: resourceCloudAccountOpenStackDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCloudAccountOpenStackImport,
+		},
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -69,7 +73,7 @@ func resourceCloudAccountOpenStackCreate(ctx context.Context, d *schema.Resource
 
 	account := toOpenStackAccount(d)
 
-	uid, err := c.CreateCloudAccountOpenStack(account)
+	uid, err := c.CreateCloudAccountOpenStack(ctx, account)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -81,14 +85,14 @@ func resourceCloudAccountOpenStackCreate(ctx context.Context, d *schema.Resource
 	return diags
 }
 
-func resourceCloudAccountOpenStackRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceCloudAccountOpenStackRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 
 	uid := d.Id()
 
-	account, err := c.GetCloudAccountOpenStack(uid)
+	account, err := c.GetCloudAccountOpenStack(ctx, uid)
 	if err != nil {
 		return diag.FromErr(err)
 	} else if account == nil {
@@ -129,6 +133,29 @@ func resourceCloudAccountOpenStackRead(_ context.Context, d *schema.ResourceData
 	return diags
 }
 
+func resourceCloudAccountOpenStackImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*client.V1Client)
+
+	uid, err := resolveProjectImportID(ctx, c, d.Id(), func(name string) (string, error) {
+		accounts, err := c.GetCloudAccountsOpenStack(ctx)
+		if err != nil {
+			return "", err
+		}
+		for _, account := range accounts {
+			if account.Metadata.Name == name {
+				return account.Metadata.UID, nil
+			}
+		}
+		return "", fmt.Errorf("openstack cloud account '%s' not found", name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(uid)
+	return []*schema.ResourceData{d}, nil
+}
+
 //
 func resourceCloudAccountOpenStackUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
@@ -138,7 +165,7 @@ func resourceCloudAccountOpenStackUpdate(ctx context.Context, d *schema.Resource
 
 	account := toOpenStackAccount(d)
 
-	err := c.UpdateCloudAccountOpenStack(account)
+	err := c.UpdateCloudAccountOpenStack(ctx, account)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -148,14 +175,14 @@ func resourceCloudAccountOpenStackUpdate(ctx context.Context, d *schema.Resource
 	return diags
 }
 
-func resourceCloudAccountOpenStackDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceCloudAccountOpenStackDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 
 	cloudAccountID := d.Id()
 
-	err := c.DeleteCloudAccountOpenStack(cloudAccountID)
+	err := c.DeleteCloudAccountOpenStack(ctx, cloudAccountID)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -2,6 +2,7 @@ package spectrocloud
 
 import (
 	"context"
+	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/spectrocloud/gomi/pkg/ptr"
@@ -17,6 +18,10 @@ func resourceCloudAccountVsphere() *schema.Resource {
 		ReadContext:   resourceCloudAccountVsphereRead,
 		UpdateContext: resourceCloudAccountVsphereUpdate,
 		DeleteContext: resourceCloudAccountVsphereDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCloudAccountVsphereImport,
+		},
+		CustomizeDiff: resourceCloudAccountVsphereCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -25,6 +30,7 @@ func resourceCloudAccountVsphere() *schema.Resource {
 			"private_cloud_gateway_id": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"vsphere_vcenter": {
 				Type:     schema.TypeString,
@@ -35,8 +41,8 @@ func resourceCloudAccountVsphere() *schema.Resource {
 				Required: true,
 			},
 			"vsphere_password": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:      schema.TypeString,
+				Required:  true,
 				Sensitive: true,
 			},
 			"vsphere_ignore_insecure_error": {
@@ -55,7 +61,7 @@ func resourceCloudAccountVsphereCreate(ctx context.Context, d *schema.ResourceDa
 
 	account := toVsphereAccount(d)
 
-	uid, err := c.CreateCloudAccountVsphere(account)
+	uid, err := c.CreateCloudAccountVsphere(ctx, account)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -67,14 +73,14 @@ func resourceCloudAccountVsphereCreate(ctx context.Context, d *schema.ResourceDa
 	return diags
 }
 
-func resourceCloudAccountVsphereRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceCloudAccountVsphereRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 
 	uid := d.Id()
 
-	account, err := c.GetCloudAccountVsphere(uid)
+	account, err := c.GetCloudAccountVsphere(ctx, uid)
 	if err != nil {
 		return diag.FromErr(err)
 	} else if account == nil {
@@ -89,11 +95,15 @@ func resourceCloudAccountVsphereRead(_ context.Context, d *schema.ResourceData,
 	if err := d.Set("private_cloud_gateway_id", account.Metadata.Annotations[OverlordUID]); err != nil {
 		return diag.FromErr(err)
 	}
-	if err := d.Set("vsphere_vcenter", *account.Spec.VcenterServer); err != nil {
-		return diag.FromErr(err)
+	if account.Spec.VcenterServer != nil {
+		if err := d.Set("vsphere_vcenter", *account.Spec.VcenterServer); err != nil {
+			return diag.FromErr(err)
+		}
 	}
-	if err := d.Set("vsphere_username", *account.Spec.Username); err != nil {
-		return diag.FromErr(err)
+	if account.Spec.Username != nil {
+		if err := d.Set("vsphere_username", *account.Spec.Username); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 	if err := d.Set("vsphere_ignore_insecure_error", account.Spec.Insecure); err != nil {
 		return diag.FromErr(err)
@@ -105,6 +115,62 @@ func resourceCloudAccountVsphereRead(_ context.Context, d *schema.ResourceData,
 	return diags
 }
 
+func resourceCloudAccountVsphereImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*client.V1Client)
+
+	uid, err := resolveProjectImportID(ctx, c, d.Id(), func(name string) (string, error) {
+		accounts, err := c.GetCloudAccountsVsphere(ctx)
+		if err != nil {
+			return "", err
+		}
+		for _, account := range accounts {
+			if account.Metadata.Name == name {
+				return account.Metadata.UID, nil
+			}
+		}
+		return "", fmt.Errorf("vsphere cloud account '%s' not found", name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(uid)
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceCloudAccountVsphereCustomizeDiff fails the plan when the account is
+// created against a private cloud gateway that is not running
+func resourceCloudAccountVsphereCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() != "" && !d.HasChange("private_cloud_gateway_id") {
+		return nil
+	}
+	if !d.NewValueKnown("private_cloud_gateway_id") {
+		return nil
+	}
+
+	c := m.(*client.V1Client)
+	return validatePrivateCloudGatewayRunning(ctx, c, d.Get("private_cloud_gateway_id").(string))
+}
+
+func validatePrivateCloudGatewayRunning(ctx context.Context, c *client.V1Client, pcgUID string) error {
+	pcg, err := c.GetPrivateCloudGateway(ctx, pcgUID)
+	if err != nil {
+		return err
+	} else if pcg == nil {
+		return fmt.Errorf("private cloud gateway '%s' not found", pcgUID)
+	}
+
+	if pcg.Status == nil || pcg.Status.State != "Running" {
+		state := "Unknown"
+		if pcg.Status != nil && pcg.Status.State != "" {
+			state = pcg.Status.State
+		}
+		return fmt.Errorf("private cloud gateway '%s' is in state '%s', it must be 'Running' to add a cloud account", pcgUID, state)
+	}
+
+	return nil
+}
+
 //
 func resourceCloudAccountVsphereUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
@@ -114,7 +180,7 @@ func resourceCloudAccountVsphereUpdate(ctx context.Context, d *schema.ResourceDa
 
 	account := toVsphereAccount(d)
 
-	err := c.UpdateCloudAccountVsphere(account)
+	err := c.UpdateCloudAccountVsphere(ctx, account)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -124,14 +190,14 @@ func resourceCloudAccountVsphereUpdate(ctx context.Context, d *schema.ResourceDa
 	return diags
 }
 
-func resourceCloudAccountVsphereDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceCloudAccountVsphereDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 
 	cloudAccountID := d.Id()
 
-	err := c.DeleteCloudAccountVsphere(cloudAccountID)
+	err := c.DeleteCloudAccountVsphere(ctx, cloudAccountID)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -144,10 +210,13 @@ func toVsphereAccount(d *schema.ResourceData) *models.V1VsphereAccount {
 		Metadata: &models.V1ObjectMeta{
 			Name: d.Get("name").(string),
 			UID:  d.Id(),
+			Annotations: map[string]string{
+				OverlordUID: d.Get("private_cloud_gateway_id").(string),
+			},
 		},
 		Spec: &models.V1VsphereCloudAccount{
-			VcenterServer: ptr.StringPtr(d.Get("vsphere_password").(string)),
-			Username:      ptr.StringPtr(d.Get("vsphere_password").(string)),
+			VcenterServer: ptr.StringPtr(d.Get("vsphere_vcenter").(string)),
+			Username:      ptr.StringPtr(d.Get("vsphere_username").(string)),
 			Password:      ptr.StringPtr(d.Get("vsphere_password").(string)),
 			Insecure:      d.Get("vsphere_ignore_insecure_error").(bool),
 		},
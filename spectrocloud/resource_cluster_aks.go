@@ -2,12 +2,13 @@ package spectrocloud
 
 import (
 	"context"
-	"log"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/spectrocloud/gomi/pkg/ptr"
 	"github.com/spectrocloud/hapi/models"
 	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client"
@@ -19,6 +20,13 @@ func resourceClusterAks() *schema.Resource {
 		ReadContext:   resourceClusterAksRead,
 		UpdateContext: resourceClusterAksUpdate,
 		DeleteContext: resourceClusterDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceClusterImporter("aks"),
+		},
+		CustomizeDiff: customdiff.All(
+			resourceClusterPacksCustomizeDiff("aks"),
+			resourceMachinePoolSizesCustomizeDiff,
+		),
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(60 * time.Minute),
@@ -70,8 +78,9 @@ func resourceClusterAks() *schema.Resource {
 										Required: true,
 									},
 									"values": {
-										Type:     schema.TypeString,
-										Required: true,
+										Type:             schema.TypeString,
+										Required:         true,
+										DiffSuppressFunc: suppressYamlDiff,
 									},
 								},
 							},
@@ -88,10 +97,81 @@ func resourceClusterAks() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"error_grace_period_minutes": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      DefaultErrorGracePeriod,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"force_delete": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"force_delete_delay": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      DefaultForceDeleteDelay,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"skip_backup_on_delete": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
 			"kubeconfig": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"health": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"conditions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"reason": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"message": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"kubernetes_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"api_endpoint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"creation_timestamp": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"cloud_config": {
 				Type:     schema.TypeList,
 				ForceNew: true,
@@ -132,8 +212,9 @@ func resourceClusterAks() *schema.Resource {
 							Required: true,
 						},
 						"values": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:             schema.TypeString,
+							Required:         true,
+							DiffSuppressFunc: suppressYamlDiff,
 						},
 					},
 				},
@@ -149,9 +230,28 @@ func resourceClusterAks() *schema.Resource {
 							//ForceNew: true,
 						},
 						"count": {
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Computed:         true,
+							DiffSuppressFunc: suppressAutoscaledCountDiff,
+						},
+						"min": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"max": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						"ready_count": {
 							Type:     schema.TypeInt,
-							Required: true,
+							Computed: true,
 						},
+						"additional_labels": schemaMachinePoolStringMap(),
+						"taints":            schemaMachinePoolTaints(),
+						"additional_tags":   schemaMachinePoolStringMap(),
 						"instance_type": {
 							Type:     schema.TypeString,
 							Required: true,
@@ -248,7 +348,7 @@ func resourceClusterAksCreate(ctx context.Context, d *schema.ResourceData, m int
 
 	cluster := toAksCluster(d)
 
-	uid, err := c.CreateClusterAks(cluster)
+	uid, err := c.CreateClusterAks(ctx, cluster)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -258,7 +358,7 @@ func resourceClusterAksCreate(ctx context.Context, d *schema.ResourceData, m int
 	stateConf := &resource.StateChangeConf{
 		Pending:    resourceClusterCreatePendingStates,
 		Target:     []string{"Running"},
-		Refresh:    resourceClusterStateRefreshFunc(c, d.Id()),
+		Refresh:    resourceClusterStateRefreshFunc(ctx, c, d, machinePoolStatusAks),
 		Timeout:    d.Timeout(schema.TimeoutCreate) - 1*time.Minute,
 		MinTimeout: 10 * time.Second,
 		Delay:      30 * time.Second,
@@ -276,14 +376,14 @@ func resourceClusterAksCreate(ctx context.Context, d *schema.ResourceData, m int
 }
 
 //goland:noinspection GoUnhandledErrorResult
-func resourceClusterAksRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceClusterAksRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 
 	uid := d.Id()
 
-	cluster, err := c.GetCluster(uid)
+	cluster, err := c.GetCluster(ctx, uid)
 	if err != nil {
 		return diag.FromErr(err)
 	} else if cluster == nil {
@@ -292,34 +392,55 @@ func resourceClusterAksRead(_ context.Context, d *schema.ResourceData, m interfa
 		return diags
 	}
 
+	if cluster.Spec.CloudConfigRef == nil {
+		return errCloudConfigNotFound(d, "")
+	}
 	configUID := cluster.Spec.CloudConfigRef.UID
 	d.Set("cloud_config_id", configUID)
 
-	if err := d.Set("tags", flattenTags(cluster.Metadata.Labels)); err != nil {
+	if err := flattenCommonAttributes(d, cluster); err != nil {
 		return diag.FromErr(err)
 	}
 
 	var config *models.V1AzureCloudConfig
-	if config, err = c.GetCloudConfigAks(configUID); err != nil {
+	if config, err = c.GetCloudConfigAks(ctx, configUID); err != nil {
+		return diag.FromErr(err)
+	} else if config == nil || config.Spec == nil {
+		return errCloudConfigNotFound(d, configUID)
+	}
+
+	if config.Spec.CloudAccountRef != nil {
+		if err := d.Set("cloud_account_id", config.Spec.CloudAccountRef.UID); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := d.Set("cloud_config", flattenClusterConfigAzure(d, config.Spec.ClusterConfig)); err != nil {
 		return diag.FromErr(err)
 	}
 
 	// Update the kubeconfig
-	kubecfg, err := c.GetClusterKubeConfig(uid)
+	kubecfg, err := c.GetClusterKubeConfig(ctx, uid)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	if err := d.Set("kubeconfig", kubecfg); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := flattenClusterStatus(d, cluster, kubecfg); err != nil {
+		return diag.FromErr(err)
+	}
 
 	mp := flattenMachinePoolConfigsAks(config.Spec.MachinePoolConfig)
+	if err := flattenMachinePoolStatus(ctx, c, d, configUID, mp, machinePoolStatusAks); err != nil {
+		return diag.FromErr(err)
+	}
+	keepMachinePoolSizes(d, mp)
 	if err := d.Set("machine_pool", mp); err != nil {
 		return diag.FromErr(err)
 	}
 
 	//read backup policy and scan policy
-	if policy, err := c.GetClusterBackupConfig(d.Id()); err != nil {
+	if policy, err := c.GetClusterBackupConfig(ctx, d.Id()); err != nil {
 		return diag.FromErr(err)
 	} else if policy != nil && policy.Spec.Config != nil {
 		if err := d.Set("backup_policy", flattenBackupPolicy(policy.Spec.Config)); err != nil {
@@ -327,7 +448,7 @@ func resourceClusterAksRead(_ context.Context, d *schema.ResourceData, m interfa
 		}
 	}
 
-	if policy, err := c.GetClusterScanConfig(d.Id()); err != nil {
+	if policy, err := c.GetClusterScanConfig(ctx, d.Id()); err != nil {
 		return diag.FromErr(err)
 	} else if policy != nil && policy.Spec.DriverSpec != nil {
 		if err := d.Set("scan_policy", flattenScanPolicy(policy.Spec.DriverSpec)); err != nil {
@@ -351,11 +472,12 @@ func flattenMachinePoolConfigsAks(machinePools []*models.V1AzureMachinePoolConfi
 		}
 
 		oi["name"] = machinePool.Name
-		oi["count"] = int(machinePool.Size)
+		flattenMachinePoolSize(oi, machinePool.Size, machinePool.MinSize, machinePool.MaxSize)
 		oi["instance_type"] = machinePool.InstanceType
 		oi["disk_size_gb"] = int(machinePool.OsDisk.DiskSizeGB)
 		oi["is_system_node_pool"] = machinePool.IsSystemNodePool
 		oi["storage_account_type"] = machinePool.OsDisk.ManagedDisk.StorageAccountType
+		flattenNodePoolMetadata(oi, machinePool.AdditionalLabels, machinePool.AdditionalTags, machinePool.Taints)
 		ois = append(ois, oi)
 	}
 	return ois
@@ -366,73 +488,32 @@ func resourceClusterAksUpdate(ctx context.Context, d *schema.ResourceData, m int
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
-
-	cloudConfigId := d.Get("cloud_config_id").(string)
-	_ = d.Get("machine_pool")
 	if d.HasChange("machine_pool") {
-		oraw, nraw := d.GetChange("machine_pool")
-		if oraw == nil {
-			oraw = new(schema.Set)
-		}
-		if nraw == nil {
-			nraw = new(schema.Set)
-		}
-
-		os := oraw.([]interface{})
-		ns := nraw.([]interface{})
-
-		osMap := make(map[string]interface{})
-		for _, mp := range os {
-			machinePool := mp.(map[string]interface{})
-			osMap[machinePool["name"].(string)] = machinePool
-		}
-
-		for _, mp := range ns {
-			machinePoolResource := mp.(map[string]interface{})
-			name := machinePoolResource["name"].(string)
-			hash := resourceMachinePoolAksHash(machinePoolResource)
-
-			machinePool := toMachinePoolAks(machinePoolResource)
-
-			var err error
-			if oldMachinePool, ok := osMap[name]; !ok {
-				log.Printf("Create machine pool %s", name)
-				err = c.CreateMachinePoolAks(cloudConfigId, machinePool)
-			} else if hash != resourceMachinePoolAksHash(oldMachinePool) {
-				log.Printf("Change in machine pool %s", name)
-				err = c.UpdateMachinePoolAks(cloudConfigId, machinePool)
-			}
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			delete(osMap, name)
-		}
-
-		// Deleted old machine pools
-		for _, mp := range osMap {
-			machinePool := mp.(map[string]interface{})
-			name := machinePool["name"].(string)
-			log.Printf("Deleted machine pool %s", name)
-			if err := c.DeleteMachinePoolAks(cloudConfigId, name); err != nil {
-				return diag.FromErr(err)
-			}
+		if err := updateMachinePools(ctx, c, d, machinePoolAdapterAks); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	if d.HasChanges("cluster_profile") {
-		if err := updateProfiles(c, d); err != nil {
+		if err := updateProfiles(ctx, c, d); err != nil {
 			return diag.FromErr(err)
 		}
 	}
 
 	if d.HasChange("backup_policy") {
-		if err := updateBackupPolicy(c, d); err != nil {
+		if err := updateBackupPolicy(ctx, c, d); err != nil {
 			return diag.FromErr(err)
 		}
 	}
 
 	if d.HasChange("scan_policy") {
-		if err := updateScanPolicy(c, d); err != nil {
+		if err := updateScanPolicy(ctx, c, d); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChanges("machine_pool", "cluster_profile") {
+		if err := waitForClusterUpdate(ctx, c, d, machinePoolStatusAks); err != nil {
 			return diag.FromErr(err)
 		}
 	}
@@ -442,6 +523,32 @@ func resourceClusterAksUpdate(ctx context.Context, d *schema.ResourceData, m int
 	return diags
 }
 
+var machinePoolAdapterAks = machinePoolAdapter{
+	hash: resourceMachinePoolAksHash,
+	create: func(ctx context.Context, c *client.V1Client, cloudConfigId string, machinePool map[string]interface{}) error {
+		return c.CreateMachinePoolAks(ctx, cloudConfigId, toMachinePoolAks(machinePool))
+	},
+	update: func(ctx context.Context, c *client.V1Client, cloudConfigId string, _, machinePool map[string]interface{}) error {
+		return c.UpdateMachinePoolAks(ctx, cloudConfigId, toMachinePoolAks(machinePool))
+	},
+	delete: func(ctx context.Context, c *client.V1Client, cloudConfigId string, name string) error {
+		return c.DeleteMachinePoolAks(ctx, cloudConfigId, name)
+	},
+}
+
+func machinePoolStatusAks(ctx context.Context, c *client.V1Client, cloudConfigId, machinePoolName string) ([]*models.V1CloudMachineStatus, error) {
+	machines, err := c.GetMachinePoolMachinesAks(ctx, cloudConfigId, machinePoolName)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*models.V1CloudMachineStatus, len(machines))
+	for i, machine := range machines {
+		statuses[i] = machine.Status
+	}
+	return statuses, nil
+}
+
 func toAksCluster(d *schema.ResourceData) *models.V1SpectroAzureClusterEntity {
 	cloudConfig := d.Get("cloud_config").([]interface{})[0].(map[string]interface{})
 	cluster := &models.V1SpectroAzureClusterEntity{
@@ -482,6 +589,8 @@ func toMachinePoolAks(machinePool interface{}) *models.V1AzureMachinePoolConfigE
 		labels = append(labels, "master")
 	}
 
+	size, minSize, maxSize := toMachinePoolSize(m)
+
 	mp := &models.V1AzureMachinePoolConfigEntity{
 		CloudConfig: &models.V1AzureMachinePoolCloudConfigEntity{
 			InstanceType: m["instance_type"].(string),
@@ -498,12 +607,15 @@ func toMachinePoolAks(machinePool interface{}) *models.V1AzureMachinePoolConfigE
 			IsSystemNodePool: m["is_system_node_pool"].(bool),
 		},
 		PoolConfig: &models.V1MachinePoolConfigEntity{
-			IsControlPlane: controlPlane,
-			Labels:         labels,
-			Name:           ptr.StringPtr(m["name"].(string)),
-			Size:           ptr.Int32Ptr(int32(m["count"].(int))),
-			MinSize:        int32(m["count"].(int)),
-			MaxSize:        int32(m["count"].(int)),
+			AdditionalLabels: toAdditionalNodePoolLabels(m),
+			AdditionalTags:   toAdditionalNodePoolTags(m),
+			Taints:           toNodePoolTaints(m),
+			IsControlPlane:   controlPlane,
+			Labels:           labels,
+			Name:             ptr.StringPtr(m["name"].(string)),
+			Size:             ptr.Int32Ptr(size),
+			MinSize:          minSize,
+			MaxSize:          maxSize,
 		},
 	}
 
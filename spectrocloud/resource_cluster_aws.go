@@ -2,12 +2,13 @@ package spectrocloud
 
 import (
 	"context"
-	"log"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/spectrocloud/gomi/pkg/ptr"
 	"github.com/spectrocloud/hapi/models"
 	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client"
@@ -19,6 +20,13 @@ func resourceClusterAws() *schema.Resource {
 		ReadContext:   resourceClusterAwsRead,
 		UpdateContext: resourceClusterAwsUpdate,
 		DeleteContext: resourceClusterDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceClusterImporter("aws"),
+		},
+		CustomizeDiff: customdiff.All(
+			resourceClusterPacksCustomizeDiff("aws"),
+			resourceMachinePoolSizesCustomizeDiff,
+		),
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(60 * time.Minute),
@@ -70,8 +78,9 @@ func resourceClusterAws() *schema.Resource {
 										Required: true,
 									},
 									"values": {
-										Type:     schema.TypeString,
-										Required: true,
+										Type:             schema.TypeString,
+										Required:         true,
+										DiffSuppressFunc: suppressYamlDiff,
 									},
 								},
 							},
@@ -102,10 +111,81 @@ func resourceClusterAws() *schema.Resource {
 				Optional:         true,
 				ValidateDiagFunc: validateOsPatchOnDemandAfter,
 			},
+			"error_grace_period_minutes": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      DefaultErrorGracePeriod,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"force_delete": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"force_delete_delay": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      DefaultForceDeleteDelay,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"skip_backup_on_delete": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
 			"kubeconfig": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"health": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"conditions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"reason": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"message": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"kubernetes_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"api_endpoint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"creation_timestamp": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"cloud_config": {
 				Type:     schema.TypeList,
 				ForceNew: true,
@@ -138,8 +218,9 @@ func resourceClusterAws() *schema.Resource {
 							Required: true,
 						},
 						"values": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:             schema.TypeString,
+							Required:         true,
+							DiffSuppressFunc: suppressYamlDiff,
 						},
 					},
 				},
@@ -169,9 +250,28 @@ func resourceClusterAws() *schema.Resource {
 							//ForceNew: true,
 						},
 						"count": {
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Computed:         true,
+							DiffSuppressFunc: suppressAutoscaledCountDiff,
+						},
+						"min": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"max": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						"ready_count": {
 							Type:     schema.TypeInt,
-							Required: true,
+							Computed: true,
 						},
+						"additional_labels": schemaMachinePoolStringMap(),
+						"taints":            schemaMachinePoolTaints(),
+						"additional_tags":   schemaMachinePoolStringMap(),
 						"instance_type": {
 							Type:     schema.TypeString,
 							Required: true,
@@ -282,7 +382,7 @@ func resourceClusterAwsCreate(ctx context.Context, d *schema.ResourceData, m int
 
 	cluster := toAwsCluster(d)
 
-	uid, err := c.CreateClusterAws(cluster)
+	uid, err := c.CreateClusterAws(ctx, cluster)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -292,7 +392,7 @@ func resourceClusterAwsCreate(ctx context.Context, d *schema.ResourceData, m int
 	stateConf := &resource.StateChangeConf{
 		Pending:    resourceClusterCreatePendingStates,
 		Target:     []string{"Running"},
-		Refresh:    resourceClusterStateRefreshFunc(c, d.Id()),
+		Refresh:    resourceClusterStateRefreshFunc(ctx, c, d, machinePoolStatusAws),
 		Timeout:    d.Timeout(schema.TimeoutCreate) - 1*time.Minute,
 		MinTimeout: 10 * time.Second,
 		Delay:      30 * time.Second,
@@ -310,14 +410,14 @@ func resourceClusterAwsCreate(ctx context.Context, d *schema.ResourceData, m int
 }
 
 //goland:noinspection GoUnhandledErrorResult
-func resourceClusterAwsRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceClusterAwsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 	//
 	uid := d.Id()
 	//
-	cluster, err := c.GetCluster(uid)
+	cluster, err := c.GetCluster(ctx, uid)
 	if err != nil {
 		return diag.FromErr(err)
 	} else if cluster == nil {
@@ -327,19 +427,25 @@ func resourceClusterAwsRead(_ context.Context, d *schema.ResourceData, m interfa
 	}
 
 	// Update the kubeconfig
-	kubeconfig, err := c.GetClusterKubeConfig(uid)
+	kubeconfig, err := c.GetClusterKubeConfig(ctx, uid)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	if err := d.Set("tags", flattenTags(cluster.Metadata.Labels)); err != nil {
+	if err := flattenCommonAttributes(d, cluster); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := flattenOsPatchConfig(d, cluster.Spec.ClusterConfig); err != nil {
 		return diag.FromErr(err)
 	}
 	if err := d.Set("kubeconfig", kubeconfig); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := flattenClusterStatus(d, cluster, kubeconfig); err != nil {
+		return diag.FromErr(err)
+	}
 
-	if policy, err := c.GetClusterBackupConfig(d.Id()); err != nil {
+	if policy, err := c.GetClusterBackupConfig(ctx, d.Id()); err != nil {
 		return diag.FromErr(err)
 	} else if policy != nil && policy.Spec.Config != nil {
 		if err := d.Set("backup_policy", flattenBackupPolicy(policy.Spec.Config)); err != nil {
@@ -347,7 +453,7 @@ func resourceClusterAwsRead(_ context.Context, d *schema.ResourceData, m interfa
 		}
 	}
 
-	if policy, err := c.GetClusterScanConfig(d.Id()); err != nil {
+	if policy, err := c.GetClusterScanConfig(ctx, d.Id()); err != nil {
 		return diag.FromErr(err)
 	} else if policy != nil && policy.Spec.DriverSpec != nil {
 		if err := d.Set("scan_policy", flattenScanPolicy(policy.Spec.DriverSpec)); err != nil {
@@ -355,15 +461,32 @@ func resourceClusterAwsRead(_ context.Context, d *schema.ResourceData, m interfa
 		}
 	}
 
-	return flattenCloudConfigAws(cluster.Spec.CloudConfigRef.UID, d, c)
+	if cluster.Spec.CloudConfigRef == nil {
+		return errCloudConfigNotFound(d, "")
+	}
+	return flattenCloudConfigAws(ctx, cluster.Spec.CloudConfigRef.UID, d, c)
 }
 
-func flattenCloudConfigAws(configUID string, d *schema.ResourceData, c *client.V1Client) diag.Diagnostics {
+func flattenCloudConfigAws(ctx context.Context, configUID string, d *schema.ResourceData, c *client.V1Client) diag.Diagnostics {
 	d.Set("cloud_config_id", configUID)
-	if config, err := c.GetCloudConfigAws(configUID); err != nil {
+	if config, err := c.GetCloudConfigAws(ctx, configUID); err != nil {
 		return diag.FromErr(err)
+	} else if config == nil || config.Spec == nil {
+		return errCloudConfigNotFound(d, configUID)
 	} else {
+		if config.Spec.CloudAccountRef != nil {
+			if err := d.Set("cloud_account_id", config.Spec.CloudAccountRef.UID); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		if err := d.Set("cloud_config", flattenClusterConfigAws(d, config.Spec.ClusterConfig)); err != nil {
+			return diag.FromErr(err)
+		}
 		mp := flattenMachinePoolConfigsAws(config.Spec.MachinePoolConfig)
+		if err := flattenMachinePoolStatus(ctx, c, d, configUID, mp, machinePoolStatusAws); err != nil {
+			return diag.FromErr(err)
+		}
+		keepMachinePoolSizes(d, mp)
 		if err := d.Set("machine_pool", mp); err != nil {
 			return diag.FromErr(err)
 		}
@@ -372,6 +495,20 @@ func flattenCloudConfigAws(configUID string, d *schema.ResourceData, c *client.V
 	return diag.Diagnostics{}
 }
 
+func flattenClusterConfigAws(d *schema.ResourceData, config *models.V1AwsClusterConfig) []interface{} {
+	if config == nil {
+		return d.Get("cloud_config").([]interface{})
+	}
+
+	cloudConfig := make(map[string]interface{})
+	cloudConfig["ssh_key_name"] = config.SSHKeyName
+	if config.Region != nil {
+		cloudConfig["region"] = *config.Region
+	}
+
+	return []interface{}{cloudConfig}
+}
+
 func flattenMachinePoolConfigsAws(machinePools []*models.V1AwsMachinePoolConfig) []interface{} {
 
 	if machinePools == nil {
@@ -386,7 +523,7 @@ func flattenMachinePoolConfigsAws(machinePools []*models.V1AwsMachinePoolConfig)
 		oi["control_plane"] = machinePool.IsControlPlane
 		oi["control_plane_as_worker"] = machinePool.UseControlPlaneAsWorker
 		oi["name"] = machinePool.Name
-		oi["count"] = int(machinePool.Size)
+		flattenMachinePoolSize(oi, machinePool.Size, machinePool.MinSize, machinePool.MaxSize)
 		oi["update_strategy"] = machinePool.UpdateStrategy.Type
 		oi["instance_type"] = machinePool.InstanceType
 		if machinePool.CapacityType != nil {
@@ -397,6 +534,7 @@ func flattenMachinePoolConfigsAws(machinePools []*models.V1AwsMachinePoolConfig)
 		}
 		oi["disk_size_gb"] = int(machinePool.RootDeviceSize)
 		oi["azs"] = machinePool.Azs
+		flattenNodePoolMetadata(oi, machinePool.AdditionalLabels, machinePool.AdditionalTags, machinePool.Taints)
 		ois[i] = oi
 	}
 
@@ -409,79 +547,32 @@ func resourceClusterAwsUpdate(ctx context.Context, d *schema.ResourceData, m int
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
-	cloudConfigId := d.Get("cloud_config_id").(string)
-
 	if d.HasChange("machine_pool") {
-		oraw, nraw := d.GetChange("machine_pool")
-		if oraw == nil {
-			oraw = new(schema.Set)
-		}
-		if nraw == nil {
-			nraw = new(schema.Set)
-		}
-
-		os := oraw.(*schema.Set)
-		ns := nraw.(*schema.Set)
-
-		osMap := make(map[string]interface{})
-		for _, mp := range os.List() {
-			machinePool := mp.(map[string]interface{})
-			osMap[machinePool["name"].(string)] = machinePool
-		}
-
-		for _, mp := range ns.List() {
-			machinePoolResource := mp.(map[string]interface{})
-			name := machinePoolResource["name"].(string)
-			hash := resourceMachinePoolAwsHash(machinePoolResource)
-
-			machinePool := toMachinePoolAws(machinePoolResource)
-
-			var err error
-			if oldMachinePool, ok := osMap[name]; !ok {
-				log.Printf("Create machine pool %s", name)
-				err = c.CreateMachinePoolAws(cloudConfigId, machinePool)
-			} else if hash != resourceMachinePoolAwsHash(oldMachinePool) {
-				log.Printf("Change in machine pool %s", name)
-				err = c.UpdateMachinePoolAws(cloudConfigId, machinePool)
-			}
-
-			if err != nil {
-				return diag.FromErr(err)
-			}
-
-			// Processed (if exists)
-			delete(osMap, name)
-		}
-
-		// Deleted old machine pools
-		for _, mp := range osMap {
-			machinePool := mp.(map[string]interface{})
-			name := machinePool["name"].(string)
-			log.Printf("Deleted machine pool %s", name)
-			if err := c.DeleteMachinePoolAws(cloudConfigId, name); err != nil {
-				return diag.FromErr(err)
-			}
+		if err := updateMachinePools(ctx, c, d, machinePoolAdapterAws); err != nil {
+			return diag.FromErr(err)
 		}
 	}
-	//TODO(saamalik) update for cluster as well
-	//if err := waitForClusterU(ctx, c, d.Id(), d.Timeout(schema.TimeoutDelete)); err != nil {
-	//	return diag.FromErr(err)
-	//}
 
 	if d.HasChanges("cluster_profile") {
-		if err := updateProfiles(c, d); err != nil {
+		if err := updateProfiles(ctx, c, d); err != nil {
 			return diag.FromErr(err)
 		}
 	}
 
 	if d.HasChange("backup_policy") {
-		if err := updateBackupPolicy(c, d); err != nil {
+		if err := updateBackupPolicy(ctx, c, d); err != nil {
 			return diag.FromErr(err)
 		}
 	}
 
 	if d.HasChange("scan_policy") {
-		if err := updateScanPolicy(c, d); err != nil {
+		if err := updateScanPolicy(ctx, c, d); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChanges("machine_pool", "cluster_profile") {
+		if err := waitForClusterUpdate(ctx, c, d, machinePoolStatusAws); err != nil {
 			return diag.FromErr(err)
 		}
 	}
@@ -491,6 +582,32 @@ func resourceClusterAwsUpdate(ctx context.Context, d *schema.ResourceData, m int
 	return diags
 }
 
+var machinePoolAdapterAws = machinePoolAdapter{
+	hash: resourceMachinePoolAwsHash,
+	create: func(ctx context.Context, c *client.V1Client, cloudConfigId string, machinePool map[string]interface{}) error {
+		return c.CreateMachinePoolAws(ctx, cloudConfigId, toMachinePoolAws(machinePool))
+	},
+	update: func(ctx context.Context, c *client.V1Client, cloudConfigId string, _, machinePool map[string]interface{}) error {
+		return c.UpdateMachinePoolAws(ctx, cloudConfigId, toMachinePoolAws(machinePool))
+	},
+	delete: func(ctx context.Context, c *client.V1Client, cloudConfigId string, name string) error {
+		return c.DeleteMachinePoolAws(ctx, cloudConfigId, name)
+	},
+}
+
+func machinePoolStatusAws(ctx context.Context, c *client.V1Client, cloudConfigId, machinePoolName string) ([]*models.V1CloudMachineStatus, error) {
+	machines, err := c.GetMachinePoolMachinesAws(ctx, cloudConfigId, machinePoolName)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*models.V1CloudMachineStatus, len(machines))
+	for i, machine := range machines {
+		statuses[i] = machine.Status
+	}
+	return statuses, nil
+}
+
 func toAwsCluster(d *schema.ResourceData) *models.V1SpectroAwsClusterEntity {
 	// gnarly, I know! =/
 	cloudConfig := d.Get("cloud_config").([]interface{})[0].(map[string]interface{})
@@ -545,6 +662,8 @@ func toMachinePoolAws(machinePool interface{}) *models.V1AwsMachinePoolConfigEnt
 		capacityType = m["capacity_type"].(string)
 	}
 
+	size, minSize, maxSize := toMachinePoolSize(m)
+
 	mp := &models.V1AwsMachinePoolConfigEntity{
 		CloudConfig: &models.V1AwsMachinePoolCloudConfigEntity{
 			Azs:          azs,
@@ -554,10 +673,15 @@ func toMachinePoolAws(machinePool interface{}) *models.V1AwsMachinePoolConfigEnt
 			RootDeviceSize: int64(m["disk_size_gb"].(int)),
 		},
 		PoolConfig: &models.V1MachinePoolConfigEntity{
-			IsControlPlane: controlPlane,
-			Labels:         labels,
-			Name:           ptr.StringPtr(m["name"].(string)),
-			Size:           ptr.Int32Ptr(int32(m["count"].(int))),
+			AdditionalLabels: toAdditionalNodePoolLabels(m),
+			AdditionalTags:   toAdditionalNodePoolTags(m),
+			Taints:           toNodePoolTaints(m),
+			IsControlPlane:   controlPlane,
+			Labels:           labels,
+			Name:             ptr.StringPtr(m["name"].(string)),
+			Size:             ptr.Int32Ptr(size),
+			MinSize:          minSize,
+			MaxSize:          maxSize,
 			UpdateStrategy: &models.V1UpdateStrategy{
 				Type: m["update_strategy"].(string),
 			},
@@ -2,12 +2,13 @@ package spectrocloud
 
 import (
 	"context"
-	"log"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/spectrocloud/gomi/pkg/ptr"
 	"github.com/spectrocloud/hapi/models"
 	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client"
@@ -19,6 +20,13 @@ func resourceClusterAzure() *schema.Resource {
 		ReadContext:   resourceClusterAzureRead,
 		UpdateContext: resourceClusterAzureUpdate,
 		DeleteContext: resourceClusterDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceClusterImporter("azure"),
+		},
+		CustomizeDiff: customdiff.All(
+			resourceClusterPacksCustomizeDiff("azure"),
+			resourceMachinePoolSizesCustomizeDiff,
+		),
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(60 * time.Minute),
@@ -70,8 +78,9 @@ func resourceClusterAzure() *schema.Resource {
 										Required: true,
 									},
 									"values": {
-										Type:     schema.TypeString,
-										Required: true,
+										Type:             schema.TypeString,
+										Required:         true,
+										DiffSuppressFunc: suppressYamlDiff,
 									},
 								},
 							},
@@ -102,10 +111,81 @@ func resourceClusterAzure() *schema.Resource {
 				Optional:         true,
 				ValidateDiagFunc: validateOsPatchOnDemandAfter,
 			},
+			"error_grace_period_minutes": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      DefaultErrorGracePeriod,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"force_delete": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"force_delete_delay": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      DefaultForceDeleteDelay,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"skip_backup_on_delete": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
 			"kubeconfig": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"health": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"conditions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"reason": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"message": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"kubernetes_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"api_endpoint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"creation_timestamp": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"cloud_config": {
 				Type:     schema.TypeList,
 				ForceNew: true,
@@ -146,8 +226,9 @@ func resourceClusterAzure() *schema.Resource {
 							Required: true,
 						},
 						"values": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:             schema.TypeString,
+							Required:         true,
+							DiffSuppressFunc: suppressYamlDiff,
 						},
 					},
 				},
@@ -177,9 +258,28 @@ func resourceClusterAzure() *schema.Resource {
 							//ForceNew: true,
 						},
 						"count": {
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Computed:         true,
+							DiffSuppressFunc: suppressAutoscaledCountDiff,
+						},
+						"min": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"max": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						"ready_count": {
 							Type:     schema.TypeInt,
-							Required: true,
+							Computed: true,
 						},
+						"additional_labels": schemaMachinePoolStringMap(),
+						"taints":            schemaMachinePoolTaints(),
+						"additional_tags":   schemaMachinePoolStringMap(),
 						"instance_type": {
 							Type:     schema.TypeString,
 							Required: true,
@@ -314,7 +414,7 @@ func resourceClusterAzureCreate(ctx context.Context, d *schema.ResourceData, m i
 
 	cluster := toAzureCluster(d)
 
-	uid, err := c.CreateClusterAzure(cluster)
+	uid, err := c.CreateClusterAzure(ctx, cluster)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -324,7 +424,7 @@ func resourceClusterAzureCreate(ctx context.Context, d *schema.ResourceData, m i
 	stateConf := &resource.StateChangeConf{
 		Pending:    resourceClusterCreatePendingStates,
 		Target:     []string{"Running"},
-		Refresh:    resourceClusterStateRefreshFunc(c, d.Id()),
+		Refresh:    resourceClusterStateRefreshFunc(ctx, c, d, machinePoolStatusAzure),
 		Timeout:    d.Timeout(schema.TimeoutCreate) - 1*time.Minute,
 		MinTimeout: 10 * time.Second,
 		Delay:      30 * time.Second,
@@ -342,14 +442,14 @@ func resourceClusterAzureCreate(ctx context.Context, d *schema.ResourceData, m i
 }
 
 //goland:noinspection GoUnhandledErrorResult
-func resourceClusterAzureRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceClusterAzureRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 
 	uid := d.Id()
 
-	cluster, err := c.GetCluster(uid)
+	cluster, err := c.GetCluster(ctx, uid)
 	if err != nil {
 		return diag.FromErr(err)
 	} else if cluster == nil {
@@ -358,11 +458,14 @@ func resourceClusterAzureRead(_ context.Context, d *schema.ResourceData, m inter
 		return diags
 	}
 
-	if err := d.Set("tags", flattenTags(cluster.Metadata.Labels)); err != nil {
+	if err := flattenCommonAttributes(d, cluster); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := flattenOsPatchConfig(d, cluster.Spec.ClusterConfig); err != nil {
 		return diag.FromErr(err)
 	}
 
-	kubecfg, err := c.GetClusterKubeConfig(uid)
+	kubecfg, err := c.GetClusterKubeConfig(ctx, uid)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -370,8 +473,11 @@ func resourceClusterAzureRead(_ context.Context, d *schema.ResourceData, m inter
 	if err := d.Set("kubeconfig", kubecfg); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := flattenClusterStatus(d, cluster, kubecfg); err != nil {
+		return diag.FromErr(err)
+	}
 
-	if policy, err := c.GetClusterBackupConfig(d.Id()); err != nil {
+	if policy, err := c.GetClusterBackupConfig(ctx, d.Id()); err != nil {
 		return diag.FromErr(err)
 	} else if policy != nil && policy.Spec.Config != nil {
 		if err := d.Set("backup_policy", flattenBackupPolicy(policy.Spec.Config)); err != nil {
@@ -379,7 +485,7 @@ func resourceClusterAzureRead(_ context.Context, d *schema.ResourceData, m inter
 		}
 	}
 
-	if policy, err := c.GetClusterScanConfig(d.Id()); err != nil {
+	if policy, err := c.GetClusterScanConfig(ctx, d.Id()); err != nil {
 		return diag.FromErr(err)
 	} else if policy != nil && policy.Spec.DriverSpec != nil {
 		if err := d.Set("scan_policy", flattenScanPolicy(policy.Spec.DriverSpec)); err != nil {
@@ -387,15 +493,32 @@ func resourceClusterAzureRead(_ context.Context, d *schema.ResourceData, m inter
 		}
 	}
 
-	return flattenCloudConfigAzure(cluster.Spec.CloudConfigRef.UID, d, c)
+	if cluster.Spec.CloudConfigRef == nil {
+		return errCloudConfigNotFound(d, "")
+	}
+	return flattenCloudConfigAzure(ctx, cluster.Spec.CloudConfigRef.UID, d, c)
 }
 
-func flattenCloudConfigAzure(configUID string, d *schema.ResourceData, c *client.V1Client) diag.Diagnostics {
+func flattenCloudConfigAzure(ctx context.Context, configUID string, d *schema.ResourceData, c *client.V1Client) diag.Diagnostics {
 	d.Set("cloud_config_id", configUID)
-	if config, err := c.GetCloudConfigAzure(configUID); err != nil {
+	if config, err := c.GetCloudConfigAzure(ctx, configUID); err != nil {
 		return diag.FromErr(err)
+	} else if config == nil || config.Spec == nil {
+		return errCloudConfigNotFound(d, configUID)
 	} else {
+		if config.Spec.CloudAccountRef != nil {
+			if err := d.Set("cloud_account_id", config.Spec.CloudAccountRef.UID); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		if err := d.Set("cloud_config", flattenClusterConfigAzure(d, config.Spec.ClusterConfig)); err != nil {
+			return diag.FromErr(err)
+		}
 		mp := flattenMachinePoolConfigsAzure(config.Spec.MachinePoolConfig)
+		if err := flattenMachinePoolStatus(ctx, c, d, configUID, mp, machinePoolStatusAzure); err != nil {
+			return diag.FromErr(err)
+		}
+		keepMachinePoolSizes(d, mp)
 		if err := d.Set("machine_pool", mp); err != nil {
 			return diag.FromErr(err)
 		}
@@ -404,6 +527,26 @@ func flattenCloudConfigAzure(configUID string, d *schema.ResourceData, c *client
 	return diag.Diagnostics{}
 }
 
+func flattenClusterConfigAzure(d *schema.ResourceData, config *models.V1AzureClusterConfig) []interface{} {
+	if config == nil {
+		return d.Get("cloud_config").([]interface{})
+	}
+
+	cloudConfig := make(map[string]interface{})
+	cloudConfig["resource_group"] = config.ResourceGroup
+	if config.SubscriptionID != nil {
+		cloudConfig["subscription_id"] = *config.SubscriptionID
+	}
+	if config.Location != nil {
+		cloudConfig["region"] = *config.Location
+	}
+	if config.SSHKey != nil {
+		cloudConfig["ssh_key"] = *config.SSHKey
+	}
+
+	return []interface{}{cloudConfig}
+}
+
 func flattenMachinePoolConfigsAzure(machinePools []*models.V1AzureMachinePoolConfig) []interface{} {
 
 	if machinePools == nil {
@@ -418,7 +561,7 @@ func flattenMachinePoolConfigsAzure(machinePools []*models.V1AzureMachinePoolCon
 		oi["control_plane"] = machinePool.IsControlPlane
 		oi["control_plane_as_worker"] = machinePool.UseControlPlaneAsWorker
 		oi["name"] = machinePool.Name
-		oi["count"] = machinePool.Size
+		flattenMachinePoolSize(oi, machinePool.Size, machinePool.MinSize, machinePool.MaxSize)
 		oi["update_strategy"] = machinePool.UpdateStrategy.Type
 		oi["instance_type"] = machinePool.InstanceType
 
@@ -432,6 +575,7 @@ func flattenMachinePoolConfigsAzure(machinePools []*models.V1AzureMachinePoolCon
 			oi["disk"] = []interface{}{d}
 		}
 
+		flattenNodePoolMetadata(oi, machinePool.AdditionalLabels, machinePool.AdditionalTags, machinePool.Taints)
 		ois[i] = oi
 	}
 
@@ -444,79 +588,32 @@ func resourceClusterAzureUpdate(ctx context.Context, d *schema.ResourceData, m i
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
-	cloudConfigId := d.Get("cloud_config_id").(string)
-
 	if d.HasChange("machine_pool") {
-		oraw, nraw := d.GetChange("machine_pool")
-		if oraw == nil {
-			oraw = new(schema.Set)
-		}
-		if nraw == nil {
-			nraw = new(schema.Set)
-		}
-
-		os := oraw.(*schema.Set)
-		ns := nraw.(*schema.Set)
-
-		osMap := make(map[string]interface{})
-		for _, mp := range os.List() {
-			machinePool := mp.(map[string]interface{})
-			osMap[machinePool["name"].(string)] = machinePool
-		}
-
-		for _, mp := range ns.List() {
-			machinePoolResource := mp.(map[string]interface{})
-			name := machinePoolResource["name"].(string)
-			hash := resourceMachinePoolAzureHash(machinePoolResource)
-
-			machinePool := toMachinePoolAzure(machinePoolResource)
-
-			var err error
-			if oldMachinePool, ok := osMap[name]; !ok {
-				log.Printf("Create machine pool %s", name)
-				err = c.CreateMachinePoolAzure(cloudConfigId, machinePool)
-			} else if hash != resourceMachinePoolAzureHash(oldMachinePool) {
-				log.Printf("Change in machine pool %s", name)
-				err = c.UpdateMachinePoolAzure(cloudConfigId, machinePool)
-			}
-
-			if err != nil {
-				return diag.FromErr(err)
-			}
-
-			// Processed (if exists)
-			delete(osMap, name)
-		}
-
-		// Deleted old machine pools
-		for _, mp := range osMap {
-			machinePool := mp.(map[string]interface{})
-			name := machinePool["name"].(string)
-			log.Printf("Deleted machine pool %s", name)
-			if err := c.DeleteMachinePoolAzure(cloudConfigId, name); err != nil {
-				return diag.FromErr(err)
-			}
+		if err := updateMachinePools(ctx, c, d, machinePoolAdapterAzure); err != nil {
+			return diag.FromErr(err)
 		}
 	}
-	//TODO(saamalik) update for cluster as well
-	//if err := waitForClusterU(ctx, c, d.Id(), d.Timeout(schema.TimeoutDelete)); err != nil {
-	//	return diag.FromErr(err)
-	//}
 
 	if d.HasChanges("cluster_profile") {
-		if err := updateProfiles(c, d); err != nil {
+		if err := updateProfiles(ctx, c, d); err != nil {
 			return diag.FromErr(err)
 		}
 	}
 
 	if d.HasChange("backup_policy") {
-		if err := updateBackupPolicy(c, d); err != nil {
+		if err := updateBackupPolicy(ctx, c, d); err != nil {
 			return diag.FromErr(err)
 		}
 	}
 
 	if d.HasChange("scan_policy") {
-		if err := updateScanPolicy(c, d); err != nil {
+		if err := updateScanPolicy(ctx, c, d); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChanges("machine_pool", "cluster_profile") {
+		if err := waitForClusterUpdate(ctx, c, d, machinePoolStatusAzure); err != nil {
 			return diag.FromErr(err)
 		}
 	}
@@ -526,6 +623,32 @@ func resourceClusterAzureUpdate(ctx context.Context, d *schema.ResourceData, m i
 	return diags
 }
 
+var machinePoolAdapterAzure = machinePoolAdapter{
+	hash: resourceMachinePoolAzureHash,
+	create: func(ctx context.Context, c *client.V1Client, cloudConfigId string, machinePool map[string]interface{}) error {
+		return c.CreateMachinePoolAzure(ctx, cloudConfigId, toMachinePoolAzure(machinePool))
+	},
+	update: func(ctx context.Context, c *client.V1Client, cloudConfigId string, _, machinePool map[string]interface{}) error {
+		return c.UpdateMachinePoolAzure(ctx, cloudConfigId, toMachinePoolAzure(machinePool))
+	},
+	delete: func(ctx context.Context, c *client.V1Client, cloudConfigId string, name string) error {
+		return c.DeleteMachinePoolAzure(ctx, cloudConfigId, name)
+	},
+}
+
+func machinePoolStatusAzure(ctx context.Context, c *client.V1Client, cloudConfigId, machinePoolName string) ([]*models.V1CloudMachineStatus, error) {
+	machines, err := c.GetMachinePoolMachinesAzure(ctx, cloudConfigId, machinePoolName)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*models.V1CloudMachineStatus, len(machines))
+	for i, machine := range machines {
+		statuses[i] = machine.Status
+	}
+	return statuses, nil
+}
+
 func toAzureCluster(d *schema.ResourceData) *models.V1SpectroAzureClusterEntity {
 	// gnarly, I know! =/
 	cloudConfig := d.Get("cloud_config").([]interface{})[0].(map[string]interface{})
@@ -586,6 +709,8 @@ func toMachinePoolAzure(machinePool interface{}) *models.V1AzureMachinePoolConfi
 		azs = append(azs, az.(string))
 	}
 
+	size, minSize, maxSize := toMachinePoolSize(m)
+
 	mp := &models.V1AzureMachinePoolConfigEntity{
 		CloudConfig: &models.V1AzureMachinePoolCloudConfigEntity{
 			Azs:          azs,
@@ -599,10 +724,15 @@ func toMachinePoolAzure(machinePool interface{}) *models.V1AzureMachinePoolConfi
 			},
 		},
 		PoolConfig: &models.V1MachinePoolConfigEntity{
-			IsControlPlane: controlPlane,
-			Labels:         labels,
-			Name:           ptr.StringPtr(m["name"].(string)),
-			Size:           ptr.Int32Ptr(int32(m["count"].(int))),
+			AdditionalLabels: toAdditionalNodePoolLabels(m),
+			AdditionalTags:   toAdditionalNodePoolTags(m),
+			Taints:           toNodePoolTaints(m),
+			IsControlPlane:   controlPlane,
+			Labels:           labels,
+			Name:             ptr.StringPtr(m["name"].(string)),
+			Size:             ptr.Int32Ptr(size),
+			MinSize:          minSize,
+			MaxSize:          maxSize,
 			UpdateStrategy: &models.V1UpdateStrategy{
 				Type: m["update_strategy"].(string),
 			},
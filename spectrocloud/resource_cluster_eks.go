@@ -3,10 +3,10 @@ package spectrocloud
 import (
 	"context"
 	"log"
-	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -21,6 +21,13 @@ func resourceClusterEks() *schema.Resource {
 		ReadContext:   resourceClusterEksRead,
 		UpdateContext: resourceClusterEksUpdate,
 		DeleteContext: resourceClusterDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceClusterImporter("eks"),
+		},
+		CustomizeDiff: customdiff.All(
+			resourceClusterPacksCustomizeDiff("eks"),
+			resourceMachinePoolSizesCustomizeDiff,
+		),
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(60 * time.Minute),
@@ -77,8 +84,9 @@ func resourceClusterEks() *schema.Resource {
 										Optional: true,
 									},
 									"values": {
-										Type:     schema.TypeString,
-										Optional: true,
+										Type:             schema.TypeString,
+										Optional:         true,
+										DiffSuppressFunc: suppressYamlDiff,
 									},
 									"manifest": {
 										Type:     schema.TypeList,
@@ -90,15 +98,9 @@ func resourceClusterEks() *schema.Resource {
 													Required: true,
 												},
 												"content": {
-													Type:     schema.TypeString,
-													Required: true,
-													DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-														// UI strips the trailing newline on save
-														if strings.TrimSpace(old) == strings.TrimSpace(new) {
-															return true
-														}
-														return false
-													},
+													Type:             schema.TypeString,
+													Required:         true,
+													DiffSuppressFunc: suppressYamlDiff,
 												},
 											},
 										},
@@ -118,10 +120,81 @@ func resourceClusterEks() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"error_grace_period_minutes": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      DefaultErrorGracePeriod,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"force_delete": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"force_delete_delay": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      DefaultForceDeleteDelay,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"skip_backup_on_delete": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
 			"kubeconfig": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"health": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"conditions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"reason": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"message": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"kubernetes_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"api_endpoint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"creation_timestamp": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"cloud_config": {
 				Type:     schema.TypeList,
 				ForceNew: true,
@@ -193,8 +266,9 @@ func resourceClusterEks() *schema.Resource {
 							Required: true,
 						},
 						"values": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:             schema.TypeString,
+							Required:         true,
+							DiffSuppressFunc: suppressYamlDiff,
 						},
 					},
 				},
@@ -214,9 +288,28 @@ func resourceClusterEks() *schema.Resource {
 							Required: true,
 						},
 						"count": {
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Computed:         true,
+							DiffSuppressFunc: suppressAutoscaledCountDiff,
+						},
+						"min": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"max": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						"ready_count": {
 							Type:     schema.TypeInt,
-							Required: true,
+							Computed: true,
 						},
+						"additional_labels": schemaMachinePoolStringMap(),
+						"taints":            schemaMachinePoolTaints(),
+						"additional_tags":   schemaMachinePoolStringMap(),
 						"instance_type": {
 							Type:     schema.TypeString,
 							Required: true,
@@ -369,7 +462,7 @@ func resourceClusterEksCreate(ctx context.Context, d *schema.ResourceData, m int
 
 	cluster := toEksCluster(d)
 
-	uid, err := c.CreateClusterEks(cluster)
+	uid, err := c.CreateClusterEks(ctx, cluster)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -379,7 +472,7 @@ func resourceClusterEksCreate(ctx context.Context, d *schema.ResourceData, m int
 	stateConf := &resource.StateChangeConf{
 		Pending:    resourceClusterCreatePendingStates,
 		Target:     []string{"Running"},
-		Refresh:    resourceClusterStateRefreshFunc(c, d.Id()),
+		Refresh:    resourceClusterStateRefreshFunc(ctx, c, d, machinePoolStatusEks),
 		Timeout:    d.Timeout(schema.TimeoutCreate) - 1*time.Minute,
 		MinTimeout: 10 * time.Second,
 		Delay:      30 * time.Second,
@@ -397,14 +490,14 @@ func resourceClusterEksCreate(ctx context.Context, d *schema.ResourceData, m int
 }
 
 //goland:noinspection GoUnhandledErrorResult
-func resourceClusterEksRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceClusterEksRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 
 	uid := d.Id()
 
-	cluster, err := c.GetCluster(uid)
+	cluster, err := c.GetCluster(ctx, uid)
 	if err != nil {
 		return diag.FromErr(err)
 	} else if cluster == nil {
@@ -413,28 +506,49 @@ func resourceClusterEksRead(_ context.Context, d *schema.ResourceData, m interfa
 		return diags
 	}
 
+	if cluster.Spec.CloudConfigRef == nil {
+		return errCloudConfigNotFound(d, "")
+	}
 	configUID := cluster.Spec.CloudConfigRef.UID
 	d.Set("cloud_config_id", configUID)
 
-	if err := d.Set("tags", flattenTags(cluster.Metadata.Labels)); err != nil {
+	if err := flattenCommonAttributes(d, cluster); err != nil {
 		return diag.FromErr(err)
 	}
 
 	var config *models.V1EksCloudConfig
-	if config, err = c.GetCloudConfigEks(configUID); err != nil {
+	if config, err = c.GetCloudConfigEks(ctx, configUID); err != nil {
+		return diag.FromErr(err)
+	} else if config == nil || config.Spec == nil {
+		return errCloudConfigNotFound(d, configUID)
+	}
+
+	if config.Spec.CloudAccountRef != nil {
+		if err := d.Set("cloud_account_id", config.Spec.CloudAccountRef.UID); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := d.Set("cloud_config", flattenClusterConfigEks(d, config.Spec.ClusterConfig, config.Spec.MachinePoolConfig)); err != nil {
 		return diag.FromErr(err)
 	}
 
 	// Update the kubeconfig
-	kubecfg, err := c.GetClusterKubeConfig(uid)
+	kubecfg, err := c.GetClusterKubeConfig(ctx, uid)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	if err := d.Set("kubeconfig", kubecfg); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := flattenClusterStatus(d, cluster, kubecfg); err != nil {
+		return diag.FromErr(err)
+	}
 
 	mp := flattenMachinePoolConfigsEks(config.Spec.MachinePoolConfig)
+	if err := flattenMachinePoolStatus(ctx, c, d, configUID, mp, machinePoolStatusEks); err != nil {
+		return diag.FromErr(err)
+	}
+	keepMachinePoolSizes(d, mp)
 	if err := d.Set("machine_pool", mp); err != nil {
 		return diag.FromErr(err)
 	}
@@ -445,7 +559,7 @@ func resourceClusterEksRead(_ context.Context, d *schema.ResourceData, m interfa
 	}
 
 	//read backup policy and scan policy
-	if policy, err := c.GetClusterBackupConfig(d.Id()); err != nil {
+	if policy, err := c.GetClusterBackupConfig(ctx, d.Id()); err != nil {
 		return diag.FromErr(err)
 	} else if policy != nil && policy.Spec.Config != nil {
 		if err := d.Set("backup_policy", flattenBackupPolicy(policy.Spec.Config)); err != nil {
@@ -453,7 +567,7 @@ func resourceClusterEksRead(_ context.Context, d *schema.ResourceData, m interfa
 		}
 	}
 
-	if policy, err := c.GetClusterScanConfig(d.Id()); err != nil {
+	if policy, err := c.GetClusterScanConfig(ctx, d.Id()); err != nil {
 		return diag.FromErr(err)
 	} else if policy != nil && policy.Spec.DriverSpec != nil {
 		if err := d.Set("scan_policy", flattenScanPolicy(policy.Spec.DriverSpec)); err != nil {
@@ -463,6 +577,44 @@ func resourceClusterEksRead(_ context.Context, d *schema.ResourceData, m interfa
 	return diags
 }
 
+func flattenClusterConfigEks(d *schema.ResourceData, config *models.V1EksClusterConfig, machinePools []*models.V1EksMachinePoolConfig) []interface{} {
+	if config == nil {
+		return d.Get("cloud_config").([]interface{})
+	}
+
+	// azs is not sent to the API, keep whatever is in state
+	cloudConfig := make(map[string]interface{})
+	if cloudConfigs := d.Get("cloud_config").([]interface{}); len(cloudConfigs) > 0 && cloudConfigs[0] != nil {
+		cloudConfig["azs"] = cloudConfigs[0].(map[string]interface{})["azs"]
+	}
+
+	cloudConfig["ssh_key_name"] = config.SSHKeyName
+	cloudConfig["vpc_id"] = config.VpcID
+	if config.Region != nil {
+		cloudConfig["region"] = *config.Region
+	}
+	if config.EndpointAccess != nil {
+		switch {
+		case config.EndpointAccess.Public && config.EndpointAccess.Private:
+			cloudConfig["endpoint_access"] = "private_and_public"
+		case config.EndpointAccess.Private:
+			cloudConfig["endpoint_access"] = "private"
+		default:
+			cloudConfig["endpoint_access"] = "public"
+		}
+		cloudConfig["public_access_cidrs"] = config.EndpointAccess.PublicCIDRs
+	}
+
+	// the subnets of the cluster live on the control plane pool
+	for _, machinePool := range machinePools {
+		if machinePool.IsControlPlane != nil && *machinePool.IsControlPlane {
+			cloudConfig["az_subnets"] = machinePool.SubnetIds
+		}
+	}
+
+	return []interface{}{cloudConfig}
+}
+
 func flattenMachinePoolConfigsEks(machinePools []*models.V1EksMachinePoolConfig) []interface{} {
 
 	if machinePools == nil {
@@ -479,7 +631,7 @@ func flattenMachinePoolConfigsEks(machinePools []*models.V1EksMachinePoolConfig)
 		}
 
 		oi["name"] = machinePool.Name
-		oi["count"] = int(machinePool.Size)
+		flattenMachinePoolSize(oi, machinePool.Size, machinePool.MinSize, machinePool.MaxSize)
 		oi["instance_type"] = machinePool.InstanceType
 		if machinePool.CapacityType != nil {
 			oi["capacity_type"] = machinePool.CapacityType
@@ -494,6 +646,7 @@ func flattenMachinePoolConfigsEks(machinePools []*models.V1EksMachinePoolConfig)
 			oi["azs"] = machinePool.Azs
 		}
 
+		flattenNodePoolMetadata(oi, machinePool.AdditionalLabels, machinePool.AdditionalTags, machinePool.Taints)
 		ois = append(ois, oi)
 	}
 
@@ -550,65 +703,15 @@ func resourceClusterEksUpdate(ctx context.Context, d *schema.ResourceData, m int
 			FargateProfiles: fargateProfiles,
 		}
 
-		err := c.UpdateFargateProfiles(cloudConfigId, fargateProfilesList)
+		err := c.UpdateFargateProfiles(ctx, cloudConfigId, fargateProfilesList)
 		if err != nil {
 			return diag.FromErr(err)
 		}
 	}
 
-	_ = d.Get("machine_pool")
-
 	if d.HasChange("machine_pool") {
-		oraw, nraw := d.GetChange("machine_pool")
-		if oraw == nil {
-			oraw = new(schema.Set)
-		}
-		if nraw == nil {
-			nraw = new(schema.Set)
-		}
-
-		os := oraw.([]interface{})
-		ns := nraw.([]interface{})
-
-		osMap := make(map[string]interface{})
-		for _, mp := range os {
-			machinePool := mp.(map[string]interface{})
-			osMap[machinePool["name"].(string)] = machinePool
-		}
-
-		for _, mp := range ns {
-			machinePoolResource := mp.(map[string]interface{})
-			name := machinePoolResource["name"].(string)
-			hash := resourceMachinePoolEksHash(machinePoolResource)
-
-			machinePool := toMachinePoolEks(machinePoolResource)
-
-			var err error
-			if oldMachinePool, ok := osMap[name]; !ok {
-				log.Printf("Create machine pool %s", name)
-				err = c.CreateMachinePoolEks(cloudConfigId, machinePool)
-			} else if hash != resourceMachinePoolEksHash(oldMachinePool) {
-				// TODO
-				log.Printf("Change in machine pool %s", name)
-				err = c.UpdateMachinePoolEks(cloudConfigId, machinePool)
-			}
-
-			if err != nil {
-				return diag.FromErr(err)
-			}
-
-			// Processed (if exists)
-			delete(osMap, name)
-		}
-
-		// Deleted old machine pools
-		for _, mp := range osMap {
-			machinePool := mp.(map[string]interface{})
-			name := machinePool["name"].(string)
-			log.Printf("Deleted machine pool %s", name)
-			if err := c.DeleteMachinePoolEks(cloudConfigId, name); err != nil {
-				return diag.FromErr(err)
-			}
+		if err := updateMachinePools(ctx, c, d, machinePoolAdapterEks); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
@@ -640,11 +743,11 @@ func resourceClusterEksUpdate(ctx context.Context, d *schema.ResourceData, m int
 	//		var err error
 	//		if oldMachinePool, ok := osMap[name]; !ok {
 	//			log.Printf("Create fargate profile %s", name)
-	//			err = c.CreateFargateProfileEks(cloudConfigId, fargateProfile)
+	//			err = c.CreateFargateProfileEks(ctx, cloudConfigId, fargateProfile)
 	//		} else if hash != resourceFargateProfileEksHash(oldMachinePool) {
 	//			// TODO
 	//			log.Printf("Change in fargate profile %s", name)
-	//			err = c.UpdateFargateProfileEks(cloudConfigId, fargateProfile)
+	//			err = c.UpdateFargateProfileEks(ctx, cloudConfigId, fargateProfile)
 	//		}
 	//
 	//		if err != nil {
@@ -660,32 +763,33 @@ func resourceClusterEksUpdate(ctx context.Context, d *schema.ResourceData, m int
 	//		fargateProfile := mp.(map[string]interface{})
 	//		name := fargateProfile["name"].(string)
 	//		log.Printf("Deleted fargate profile %s", name)
-	//		if err := c.DeleteFargateProfileEks(cloudConfigId, name); err != nil {
+	//		if err := c.DeleteFargateProfileEks(ctx, cloudConfigId, name); err != nil {
 	//			return diag.FromErr(err)
 	//		}
 	//	}
 	//}
 	//
 
-	//TODO(saamalik) update for cluster as well
-	//if err := waitForClusterU(ctx, c, d.Id(), d.Timeout(schema.TimeoutDelete)); err != nil {
-	//	return diag.FromErr(err)
-	//}
-
 	if d.HasChanges("cluster_profile") {
-		if err := updateProfiles(c, d); err != nil {
+		if err := updateProfiles(ctx, c, d); err != nil {
 			return diag.FromErr(err)
 		}
 	}
 
 	if d.HasChange("backup_policy") {
-		if err := updateBackupPolicy(c, d); err != nil {
+		if err := updateBackupPolicy(ctx, c, d); err != nil {
 			return diag.FromErr(err)
 		}
 	}
 
 	if d.HasChange("scan_policy") {
-		if err := updateScanPolicy(c, d); err != nil {
+		if err := updateScanPolicy(ctx, c, d); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChanges("machine_pool", "fargate_profile", "cluster_profile") {
+		if err := waitForClusterUpdate(ctx, c, d, machinePoolStatusEks); err != nil {
 			return diag.FromErr(err)
 		}
 	}
@@ -695,6 +799,32 @@ func resourceClusterEksUpdate(ctx context.Context, d *schema.ResourceData, m int
 	return diags
 }
 
+var machinePoolAdapterEks = machinePoolAdapter{
+	hash: resourceMachinePoolEksHash,
+	create: func(ctx context.Context, c *client.V1Client, cloudConfigId string, machinePool map[string]interface{}) error {
+		return c.CreateMachinePoolEks(ctx, cloudConfigId, toMachinePoolEks(machinePool))
+	},
+	update: func(ctx context.Context, c *client.V1Client, cloudConfigId string, _, machinePool map[string]interface{}) error {
+		return c.UpdateMachinePoolEks(ctx, cloudConfigId, toMachinePoolEks(machinePool))
+	},
+	delete: func(ctx context.Context, c *client.V1Client, cloudConfigId string, name string) error {
+		return c.DeleteMachinePoolEks(ctx, cloudConfigId, name)
+	},
+}
+
+func machinePoolStatusEks(ctx context.Context, c *client.V1Client, cloudConfigId, machinePoolName string) ([]*models.V1CloudMachineStatus, error) {
+	machines, err := c.GetMachinePoolMachinesEks(ctx, cloudConfigId, machinePoolName)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*models.V1CloudMachineStatus, len(machines))
+	for i, machine := range machines {
+		statuses[i] = machine.Status
+	}
+	return statuses, nil
+}
+
 func toEksCluster(d *schema.ResourceData) *models.V1SpectroEksClusterEntity {
 	// gnarly, I know! =/
 	cloudConfig := d.Get("cloud_config").([]interface{})[0].(map[string]interface{})
@@ -796,6 +926,8 @@ func toMachinePoolEks(machinePool interface{}) *models.V1EksMachinePoolConfigEnt
 		capacityType = m["capacity_type"].(string)
 	}
 
+	size, minSize, maxSize := toMachinePoolSize(m)
+
 	mp := &models.V1EksMachinePoolConfigEntity{
 		CloudConfig: &models.V1EksMachineCloudConfigEntity{
 			RootDeviceSize: int64(m["disk_size_gb"].(int)),
@@ -805,12 +937,15 @@ func toMachinePoolEks(machinePool interface{}) *models.V1EksMachinePoolConfigEnt
 			Subnets:        subnets,
 		},
 		PoolConfig: &models.V1MachinePoolConfigEntity{
-			IsControlPlane: controlPlane,
-			Labels:         labels,
-			Name:           ptr.StringPtr(m["name"].(string)),
-			Size:           ptr.Int32Ptr(int32(m["count"].(int))),
-			MinSize:        int32(m["count"].(int)),
-			MaxSize:        int32(m["count"].(int)),
+			AdditionalLabels: toAdditionalNodePoolLabels(m),
+			AdditionalTags:   toAdditionalNodePoolTags(m),
+			Taints:           toNodePoolTaints(m),
+			IsControlPlane:   controlPlane,
+			Labels:           labels,
+			Name:             ptr.StringPtr(m["name"].(string)),
+			Size:             ptr.Int32Ptr(size),
+			MinSize:          minSize,
+			MaxSize:          maxSize,
 		},
 	}
 
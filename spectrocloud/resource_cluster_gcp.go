@@ -2,12 +2,13 @@ package spectrocloud
 
 import (
 	"context"
-	"log"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/spectrocloud/gomi/pkg/ptr"
 	"github.com/spectrocloud/hapi/models"
 	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client"
@@ -19,6 +20,13 @@ func resourceClusterGcp() *schema.Resource {
 		ReadContext:   resourceClusterGcpRead,
 		UpdateContext: resourceClusterGcpUpdate,
 		DeleteContext: resourceClusterDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceClusterImporter("gcp"),
+		},
+		CustomizeDiff: customdiff.All(
+			resourceClusterPacksCustomizeDiff("gcp"),
+			resourceMachinePoolSizesCustomizeDiff,
+		),
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(60 * time.Minute),
@@ -70,8 +78,9 @@ func resourceClusterGcp() *schema.Resource {
 										Required: true,
 									},
 									"values": {
-										Type:     schema.TypeString,
-										Required: true,
+										Type:             schema.TypeString,
+										Required:         true,
+										DiffSuppressFunc: suppressYamlDiff,
 									},
 								},
 							},
@@ -102,10 +111,81 @@ func resourceClusterGcp() *schema.Resource {
 				Optional:         true,
 				ValidateDiagFunc: validateOsPatchOnDemandAfter,
 			},
+			"error_grace_period_minutes": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      DefaultErrorGracePeriod,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"force_delete": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"force_delete_delay": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      DefaultForceDeleteDelay,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"skip_backup_on_delete": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
 			"kubeconfig": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"health": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"conditions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"reason": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"message": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"kubernetes_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"api_endpoint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"creation_timestamp": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"cloud_config": {
 				Type:     schema.TypeList,
 				ForceNew: true,
@@ -142,8 +222,9 @@ func resourceClusterGcp() *schema.Resource {
 							Required: true,
 						},
 						"values": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:             schema.TypeString,
+							Required:         true,
+							DiffSuppressFunc: suppressYamlDiff,
 						},
 					},
 				},
@@ -173,9 +254,28 @@ func resourceClusterGcp() *schema.Resource {
 							//ForceNew: true,
 						},
 						"count": {
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Computed:         true,
+							DiffSuppressFunc: suppressAutoscaledCountDiff,
+						},
+						"min": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"max": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						"ready_count": {
 							Type:     schema.TypeInt,
-							Required: true,
+							Computed: true,
 						},
+						"additional_labels": schemaMachinePoolStringMap(),
+						"taints":            schemaMachinePoolTaints(),
+						"additional_tags":   schemaMachinePoolStringMap(),
 						"instance_type": {
 							Type:     schema.TypeString,
 							Required: true,
@@ -278,7 +378,7 @@ func resourceClusterGcpCreate(ctx context.Context, d *schema.ResourceData, m int
 
 	cluster := toGcpCluster(d)
 
-	uid, err := c.CreateClusterGcp(cluster)
+	uid, err := c.CreateClusterGcp(ctx, cluster)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -288,7 +388,7 @@ func resourceClusterGcpCreate(ctx context.Context, d *schema.ResourceData, m int
 	stateConf := &resource.StateChangeConf{
 		Pending:    resourceClusterCreatePendingStates,
 		Target:     []string{"Running"},
-		Refresh:    resourceClusterStateRefreshFunc(c, d.Id()),
+		Refresh:    resourceClusterStateRefreshFunc(ctx, c, d, machinePoolStatusGcp),
 		Timeout:    d.Timeout(schema.TimeoutCreate) - 1*time.Minute,
 		MinTimeout: 10 * time.Second,
 		Delay:      30 * time.Second,
@@ -306,14 +406,14 @@ func resourceClusterGcpCreate(ctx context.Context, d *schema.ResourceData, m int
 }
 
 //goland:noinspection GoUnhandledErrorResult
-func resourceClusterGcpRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceClusterGcpRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 
 	uid := d.Id()
 
-	cluster, err := c.GetCluster(uid)
+	cluster, err := c.GetCluster(ctx, uid)
 	if err != nil {
 		return diag.FromErr(err)
 	} else if cluster == nil {
@@ -322,19 +422,25 @@ func resourceClusterGcpRead(_ context.Context, d *schema.ResourceData, m interfa
 		return diags
 	}
 
-	if err := d.Set("tags", flattenTags(cluster.Metadata.Labels)); err != nil {
+	if err := flattenCommonAttributes(d, cluster); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := flattenOsPatchConfig(d, cluster.Spec.ClusterConfig); err != nil {
 		return diag.FromErr(err)
 	}
 
-	kubecfg, err := c.GetClusterKubeConfig(uid)
+	kubecfg, err := c.GetClusterKubeConfig(ctx, uid)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	if err := d.Set("kubeconfig", kubecfg); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := flattenClusterStatus(d, cluster, kubecfg); err != nil {
+		return diag.FromErr(err)
+	}
 
-	if policy, err := c.GetClusterBackupConfig(d.Id()); err != nil {
+	if policy, err := c.GetClusterBackupConfig(ctx, d.Id()); err != nil {
 		return diag.FromErr(err)
 	} else if policy != nil && policy.Spec.Config != nil {
 		if err := d.Set("backup_policy", flattenBackupPolicy(policy.Spec.Config)); err != nil {
@@ -342,7 +448,7 @@ func resourceClusterGcpRead(_ context.Context, d *schema.ResourceData, m interfa
 		}
 	}
 
-	if policy, err := c.GetClusterScanConfig(d.Id()); err != nil {
+	if policy, err := c.GetClusterScanConfig(ctx, d.Id()); err != nil {
 		return diag.FromErr(err)
 	} else if policy != nil && policy.Spec.DriverSpec != nil {
 		if err := d.Set("scan_policy", flattenScanPolicy(policy.Spec.DriverSpec)); err != nil {
@@ -350,15 +456,32 @@ func resourceClusterGcpRead(_ context.Context, d *schema.ResourceData, m interfa
 		}
 	}
 
-	return flattenCloudConfigGcp(cluster.Spec.CloudConfigRef.UID, d, c)
+	if cluster.Spec.CloudConfigRef == nil {
+		return errCloudConfigNotFound(d, "")
+	}
+	return flattenCloudConfigGcp(ctx, cluster.Spec.CloudConfigRef.UID, d, c)
 }
 
-func flattenCloudConfigGcp(configUID string, d *schema.ResourceData, c *client.V1Client) diag.Diagnostics {
+func flattenCloudConfigGcp(ctx context.Context, configUID string, d *schema.ResourceData, c *client.V1Client) diag.Diagnostics {
 	d.Set("cloud_config_id", configUID)
-	if config, err := c.GetCloudConfigGcp(configUID); err != nil {
+	if config, err := c.GetCloudConfigGcp(ctx, configUID); err != nil {
 		return diag.FromErr(err)
+	} else if config == nil || config.Spec == nil {
+		return errCloudConfigNotFound(d, configUID)
 	} else {
+		if config.Spec.CloudAccountRef != nil {
+			if err := d.Set("cloud_account_id", config.Spec.CloudAccountRef.UID); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		if err := d.Set("cloud_config", flattenClusterConfigGcp(d, config.Spec.ClusterConfig)); err != nil {
+			return diag.FromErr(err)
+		}
 		mp := flattenMachinePoolConfigsGcp(config.Spec.MachinePoolConfig)
+		if err := flattenMachinePoolStatus(ctx, c, d, configUID, mp, machinePoolStatusGcp); err != nil {
+			return diag.FromErr(err)
+		}
+		keepMachinePoolSizes(d, mp)
 		if err := d.Set("machine_pool", mp); err != nil {
 			return diag.FromErr(err)
 		}
@@ -367,6 +490,23 @@ func flattenCloudConfigGcp(configUID string, d *schema.ResourceData, c *client.V
 	return diag.Diagnostics{}
 }
 
+func flattenClusterConfigGcp(d *schema.ResourceData, config *models.V1GcpClusterConfig) []interface{} {
+	if config == nil {
+		return d.Get("cloud_config").([]interface{})
+	}
+
+	cloudConfig := make(map[string]interface{})
+	cloudConfig["network"] = config.Network
+	if config.Project != nil {
+		cloudConfig["project"] = *config.Project
+	}
+	if config.Region != nil {
+		cloudConfig["region"] = *config.Region
+	}
+
+	return []interface{}{cloudConfig}
+}
+
 func flattenMachinePoolConfigsGcp(machinePools []*models.V1GcpMachinePoolConfig) []interface{} {
 
 	if machinePools == nil {
@@ -381,7 +521,7 @@ func flattenMachinePoolConfigsGcp(machinePools []*models.V1GcpMachinePoolConfig)
 		oi["control_plane"] = machinePool.IsControlPlane
 		oi["control_plane_as_worker"] = machinePool.UseControlPlaneAsWorker
 		oi["name"] = machinePool.Name
-		oi["count"] = int(machinePool.Size)
+		flattenMachinePoolSize(oi, machinePool.Size, machinePool.MinSize, machinePool.MaxSize)
 		oi["update_strategy"] = machinePool.UpdateStrategy.Type
 		oi["instance_type"] = *machinePool.InstanceType
 
@@ -389,6 +529,7 @@ func flattenMachinePoolConfigsGcp(machinePools []*models.V1GcpMachinePoolConfig)
 
 		oi["azs"] = machinePool.Azs
 
+		flattenNodePoolMetadata(oi, machinePool.AdditionalLabels, machinePool.AdditionalTags, machinePool.Taints)
 		ois[i] = oi
 	}
 
@@ -401,79 +542,32 @@ func resourceClusterGcpUpdate(ctx context.Context, d *schema.ResourceData, m int
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
-	cloudConfigId := d.Get("cloud_config_id").(string)
-
 	if d.HasChange("machine_pool") {
-		oraw, nraw := d.GetChange("machine_pool")
-		if oraw == nil {
-			oraw = new(schema.Set)
-		}
-		if nraw == nil {
-			nraw = new(schema.Set)
-		}
-
-		os := oraw.(*schema.Set)
-		ns := nraw.(*schema.Set)
-
-		osMap := make(map[string]interface{})
-		for _, mp := range os.List() {
-			machinePool := mp.(map[string]interface{})
-			osMap[machinePool["name"].(string)] = machinePool
-		}
-
-		for _, mp := range ns.List() {
-			machinePoolResource := mp.(map[string]interface{})
-			name := machinePoolResource["name"].(string)
-			hash := resourceMachinePoolGcpHash(machinePoolResource)
-
-			machinePool := toMachinePoolGcp(machinePoolResource)
-
-			var err error
-			if oldMachinePool, ok := osMap[name]; !ok {
-				log.Printf("Create machine pool %s", name)
-				err = c.CreateMachinePoolGcp(cloudConfigId, machinePool)
-			} else if hash != resourceMachinePoolGcpHash(oldMachinePool) {
-				log.Printf("Change in machine pool %s", name)
-				err = c.UpdateMachinePoolGcp(cloudConfigId, machinePool)
-			}
-
-			if err != nil {
-				return diag.FromErr(err)
-			}
-
-			// Processed (if exists)
-			delete(osMap, name)
-		}
-
-		// Deleted old machine pools
-		for _, mp := range osMap {
-			machinePool := mp.(map[string]interface{})
-			name := machinePool["name"].(string)
-			log.Printf("Deleted machine pool %s", name)
-			if err := c.DeleteMachinePoolGcp(cloudConfigId, name); err != nil {
-				return diag.FromErr(err)
-			}
+		if err := updateMachinePools(ctx, c, d, machinePoolAdapterGcp); err != nil {
+			return diag.FromErr(err)
 		}
 	}
-	//TODO(saamalik) update for cluster as well
-	//if err := waitForClusterU(ctx, c, d.Id(), d.Timeout(schema.TimeoutDelete)); err != nil {
-	//	return diag.FromErr(err)
-	//}
 
 	if d.HasChanges("cluster_profile") {
-		if err := updateProfiles(c, d); err != nil {
+		if err := updateProfiles(ctx, c, d); err != nil {
 			return diag.FromErr(err)
 		}
 	}
 
 	if d.HasChange("backup_policy") {
-		if err := updateBackupPolicy(c, d); err != nil {
+		if err := updateBackupPolicy(ctx, c, d); err != nil {
 			return diag.FromErr(err)
 		}
 	}
 
 	if d.HasChange("scan_policy") {
-		if err := updateScanPolicy(c, d); err != nil {
+		if err := updateScanPolicy(ctx, c, d); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChanges("machine_pool", "cluster_profile") {
+		if err := waitForClusterUpdate(ctx, c, d, machinePoolStatusGcp); err != nil {
 			return diag.FromErr(err)
 		}
 	}
@@ -483,6 +577,32 @@ func resourceClusterGcpUpdate(ctx context.Context, d *schema.ResourceData, m int
 	return diags
 }
 
+var machinePoolAdapterGcp = machinePoolAdapter{
+	hash: resourceMachinePoolGcpHash,
+	create: func(ctx context.Context, c *client.V1Client, cloudConfigId string, machinePool map[string]interface{}) error {
+		return c.CreateMachinePoolGcp(ctx, cloudConfigId, toMachinePoolGcp(machinePool))
+	},
+	update: func(ctx context.Context, c *client.V1Client, cloudConfigId string, _, machinePool map[string]interface{}) error {
+		return c.UpdateMachinePoolGcp(ctx, cloudConfigId, toMachinePoolGcp(machinePool))
+	},
+	delete: func(ctx context.Context, c *client.V1Client, cloudConfigId string, name string) error {
+		return c.DeleteMachinePoolGcp(ctx, cloudConfigId, name)
+	},
+}
+
+func machinePoolStatusGcp(ctx context.Context, c *client.V1Client, cloudConfigId, machinePoolName string) ([]*models.V1CloudMachineStatus, error) {
+	machines, err := c.GetMachinePoolMachinesGcp(ctx, cloudConfigId, machinePoolName)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*models.V1CloudMachineStatus, len(machines))
+	for i, machine := range machines {
+		statuses[i] = machine.Status
+	}
+	return statuses, nil
+}
+
 func toGcpCluster(d *schema.ResourceData) *models.V1SpectroGcpClusterEntity {
 	// gnarly, I know! =/
 	cloudConfig := d.Get("cloud_config").([]interface{})[0].(map[string]interface{})
@@ -533,6 +653,8 @@ func toMachinePoolGcp(machinePool interface{}) *models.V1GcpMachinePoolConfigEnt
 		azs = append(azs, az.(string))
 	}
 
+	size, minSize, maxSize := toMachinePoolSize(m)
+
 	mp := &models.V1GcpMachinePoolConfigEntity{
 		CloudConfig: &models.V1GcpMachinePoolCloudConfigEntity{
 			Azs:            azs,
@@ -540,10 +662,15 @@ func toMachinePoolGcp(machinePool interface{}) *models.V1GcpMachinePoolConfigEnt
 			RootDeviceSize: int64(m["disk_size_gb"].(int)),
 		},
 		PoolConfig: &models.V1MachinePoolConfigEntity{
-			IsControlPlane: controlPlane,
-			Labels:         labels,
-			Name:           ptr.StringPtr(m["name"].(string)),
-			Size:           ptr.Int32Ptr(int32(m["count"].(int))),
+			AdditionalLabels: toAdditionalNodePoolLabels(m),
+			AdditionalTags:   toAdditionalNodePoolTags(m),
+			Taints:           toNodePoolTaints(m),
+			IsControlPlane:   controlPlane,
+			Labels:           labels,
+			Name:             ptr.StringPtr(m["name"].(string)),
+			Size:             ptr.Int32Ptr(size),
+			MinSize:          minSize,
+			MaxSize:          maxSize,
 			UpdateStrategy: &models.V1UpdateStrategy{
 				Type: m["update_strategy"].(string),
 			},
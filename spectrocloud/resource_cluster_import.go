@@ -13,6 +13,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceClusterImport() *schema.Resource {
@@ -21,6 +22,10 @@ func resourceClusterImport() *schema.Resource {
 		ReadContext:   resourceCloudClusterRead,
 		UpdateContext: resourceCloudClusterUpdate,
 		DeleteContext: resourceClusterDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCloudClusterImporter,
+		},
+		CustomizeDiff: resourceClusterImportPacksCustomizeDiff,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(10 * time.Minute),
@@ -45,6 +50,72 @@ func resourceClusterImport() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"health": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"conditions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"reason": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"message": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"kubernetes_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"api_endpoint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"creation_timestamp": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"error_grace_period_minutes": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      DefaultErrorGracePeriod,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"force_delete": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"force_delete_delay": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      DefaultForceDeleteDelay,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
 			"cluster_import_manifest_apply_command": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -71,8 +142,9 @@ func resourceClusterImport() *schema.Resource {
 							Required: true,
 						},
 						"values": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:             schema.TypeString,
+							Required:         true,
+							DiffSuppressFunc: suppressYamlDiff,
 						},
 					},
 				},
@@ -84,14 +156,14 @@ func resourceClusterImport() *schema.Resource {
 func resourceCloudClusterImport(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
-	uid, err := cloudClusterImportFunc(c, d)
+	uid, err := cloudClusterImportFunc(ctx, c, d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	d.SetId(uid)
 	stateConf := &resource.StateChangeConf{
 		Target:     []string{"Pending"},
-		Refresh:    resourceClusterStateRefreshFunc(c, d.Id()),
+		Refresh:    resourceClusterStateRefreshFunc(ctx, c, d, nil),
 		Timeout:    d.Timeout(schema.TimeoutCreate) - 1*time.Minute,
 		MinTimeout: 1 * time.Second,
 		Delay:      5 * time.Second,
@@ -106,7 +178,7 @@ func resourceCloudClusterImport(ctx context.Context, d *schema.ResourceData, m i
 	resourceCloudClusterRead(ctx, d, m)
 
 	if profiles := toCloudClusterProfiles(d); profiles != nil {
-		if err := c.UpdateClusterProfileValues(uid, profiles); err != nil {
+		if err := c.UpdateClusterProfileValues(ctx, uid, profiles); err != nil {
 			return diag.FromErr(err)
 		}
 	}
@@ -114,13 +186,11 @@ func resourceCloudClusterImport(ctx context.Context, d *schema.ResourceData, m i
 }
 
 func resourceCloudClusterRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	cloudType := d.Get("cloud").(string)
-
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 	uid := d.Id()
-	cluster, err := c.GetCluster(uid)
+	cluster, err := c.GetCluster(ctx, uid)
 	if err != nil {
 		return diag.FromErr(err)
 	} else if cluster == nil {
@@ -128,34 +198,78 @@ func resourceCloudClusterRead(ctx context.Context, d *schema.ResourceData, m int
 		return diags
 	}
 
-	if err := resourceCloudClusterImportManoifests(cluster, d, c); err != nil {
+	if err := resourceCloudClusterImportManoifests(ctx, cluster, d, c); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("name", cluster.Metadata.Name); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := d.Set("cloud", cluster.Spec.CloudType); err != nil {
+		return diag.FromErr(err)
+	}
+	if _, found := d.GetOk("cluster_profile_id"); !found && len(cluster.Spec.ClusterProfileTemplates) > 0 {
+		if err := d.Set("cluster_profile_id", cluster.Spec.ClusterProfileTemplates[0].UID); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 
 	if cluster.Status.State == "Running" {
-		switch cloudType {
-		case "aws":
-			return flattenCloudConfigAws(cluster.Spec.CloudConfigRef.UID, d, c)
-		case "azure":
-			return flattenCloudConfigAzure(cluster.Spec.CloudConfigRef.UID, d, c)
-		case "gcp":
-			return flattenCloudConfigGcp(cluster.Spec.CloudConfigRef.UID, d, c)
-		case "vsphere":
-			return flattenCloudConfigVsphere(cluster.Spec.CloudConfigRef.UID, d, c)
+		if err := d.Set("cloud_config_id", cluster.Spec.CloudConfigRef.UID); err != nil {
+			return diag.FromErr(err)
 		}
-		return diag.FromErr(fmt.Errorf("failed to import cluster as cloud type '%s' is invalid", cloudType))
+	}
+
+	kubeconfig := ""
+	if cluster.Status.State == "Running" {
+		if kubeconfig, err = c.GetClusterKubeConfig(ctx, uid); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := flattenClusterStatus(d, cluster, kubeconfig); err != nil {
+		return diag.FromErr(err)
 	}
 
 	return diag.Diagnostics{}
 }
 
-func resourceCloudClusterImportManoifests(cluster *models.V1SpectroCluster, d *schema.ResourceData, c *client.V1Client) error {
+func resourceCloudClusterImporter(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*client.V1Client)
+
+	cluster, err := getImportCluster(ctx, c, d.Id())
+	if err != nil {
+		return nil, err
+	}
+	if cluster.Status == nil || cluster.Status.ClusterImport == nil || !cluster.Status.ClusterImport.IsBrownfield {
+		return nil, fmt.Errorf("cluster '%s' was not imported into Spectro Cloud, use the spectrocloud_cluster_%s resource instead", d.Id(), cluster.Spec.CloudType)
+	}
+	if diags := validateCloudType(cluster.Spec.CloudType, nil); diags.HasError() {
+		return nil, fmt.Errorf("cluster '%s' has unsupported cloud type '%s'", d.Id(), cluster.Spec.CloudType)
+	}
+
+	d.SetId(cluster.Metadata.UID)
+	if err := d.Set("error_grace_period_minutes", DefaultErrorGracePeriod); err != nil {
+		return nil, err
+	}
+	if err := d.Set("deletion_protection", false); err != nil {
+		return nil, err
+	}
+	if err := d.Set("force_delete", false); err != nil {
+		return nil, err
+	}
+	if err := d.Set("force_delete_delay", DefaultForceDeleteDelay); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceCloudClusterImportManoifests(ctx context.Context, cluster *models.V1SpectroCluster, d *schema.ResourceData, c *client.V1Client) error {
 	if cluster.Status != nil && cluster.Status.ClusterImport != nil && cluster.Status.ClusterImport.IsBrownfield {
 		if err := d.Set("cluster_import_manifest_apply_command", cluster.Status.ClusterImport.ImportLink); err != nil {
 			return err
 		}
 
-		importManifest, err := c.GetClusterImportManifest(cluster.Metadata.UID)
+		importManifest, err := c.GetClusterImportManifest(ctx, cluster.Metadata.UID)
 		if err != nil {
 			return err
 		}
@@ -166,23 +280,23 @@ func resourceCloudClusterImportManoifests(cluster *models.V1SpectroCluster, d *s
 	return nil
 }
 
-func cloudClusterImportFunc(c *client.V1Client, d *schema.ResourceData) (string, error) {
+func cloudClusterImportFunc(ctx context.Context, c *client.V1Client, d *schema.ResourceData) (string, error) {
 	meta := toClusterMeta(d)
 	cloudType := d.Get("cloud").(string)
 	switch cloudType {
 	case "aws":
-		return c.ImportClusterAws(meta)
+		return c.ImportClusterAws(ctx, meta)
 	case "azure":
-		return c.ImportClusterAzure(meta)
+		return c.ImportClusterAzure(ctx, meta)
 	case "gcp":
-		return c.ImportClusterGcp(meta)
+		return c.ImportClusterGcp(ctx, meta)
 	case "vsphere":
-		return c.ImportClusterVsphere(meta)
+		return c.ImportClusterVsphere(ctx, meta)
 	}
 	return "", fmt.Errorf("failed to find cloud type %s", cloudType)
 }
 
-func resourceCloudClusterUpdate(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceCloudClusterUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
 
@@ -199,7 +313,7 @@ func resourceCloudClusterUpdate(_ context.Context, d *schema.ResourceData, m int
 		UID:        clusterProfileId,
 	})
 
-	err := c.UpdateClusterProfileValues(d.Id(), &models.V1SpectroClusterProfiles{
+	err := c.UpdateClusterProfileValues(ctx, d.Id(), &models.V1SpectroClusterProfiles{
 		Profiles: profiles,
 	})
 	if err != nil {
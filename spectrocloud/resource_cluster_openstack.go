@@ -2,13 +2,13 @@ package spectrocloud
 
 import (
 	"context"
-	"log"
 	"sort"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/spectrocloud/gomi/pkg/ptr"
 	"github.com/spectrocloud/hapi/models"
 	"github.com/spectrocloud/terraform-provider-spectrocloud/pkg/client"
@@ -20,6 +20,10 @@ func resourceClusterOpenStack() *schema.Resource {
 		ReadContext:   resourceClusterOpenStackRead,
 		UpdateContext: resourceClusterOpenStackUpdate,
 		DeleteContext: resourceClusterDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceClusterImporter("openstack"),
+		},
+		CustomizeDiff: resourceClusterPacksCustomizeDiff("openstack"),
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(180 * time.Minute),
@@ -70,8 +74,9 @@ func resourceClusterOpenStack() *schema.Resource {
 										Required: true,
 									},
 									"values": {
-										Type:     schema.TypeString,
-										Required: true,
+										Type:             schema.TypeString,
+										Required:         true,
+										DiffSuppressFunc: suppressYamlDiff,
 									},
 								},
 							},
@@ -102,10 +107,81 @@ func resourceClusterOpenStack() *schema.Resource {
 				Optional:         true,
 				ValidateDiagFunc: validateOsPatchOnDemandAfter,
 			},
+			"error_grace_period_minutes": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      DefaultErrorGracePeriod,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"force_delete": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"force_delete_delay": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      DefaultForceDeleteDelay,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"skip_backup_on_delete": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
 			"kubeconfig": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"health": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"conditions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"reason": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"message": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"kubernetes_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"api_endpoint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"creation_timestamp": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"cloud_config": {
 				Type:     schema.TypeList,
 				ForceNew: true,
@@ -167,8 +243,9 @@ func resourceClusterOpenStack() *schema.Resource {
 							Required: true,
 						},
 						"values": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:             schema.TypeString,
+							Required:         true,
+							DiffSuppressFunc: suppressYamlDiff,
 						},
 					},
 				},
@@ -196,6 +273,13 @@ func resourceClusterOpenStack() *schema.Resource {
 							Type:     schema.TypeInt,
 							Required: true,
 						},
+						"ready_count": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"additional_labels": schemaMachinePoolStringMap(),
+						"taints":            schemaMachinePoolTaints(),
+						"additional_tags":   schemaMachinePoolStringMap(),
 						"update_strategy": {
 							Type:     schema.TypeString,
 							Optional: true,
@@ -295,7 +379,7 @@ func resourceClusterOpenStackCreate(ctx context.Context, d *schema.ResourceData,
 
 	cluster := toOpenStackCluster(d)
 
-	uid, err := c.CreateClusterOpenStack(cluster)
+	uid, err := c.CreateClusterOpenStack(ctx, cluster)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -305,7 +389,7 @@ func resourceClusterOpenStackCreate(ctx context.Context, d *schema.ResourceData,
 	stateConf := &resource.StateChangeConf{
 		Pending:    resourceClusterCreatePendingStates,
 		Target:     []string{"Running"},
-		Refresh:    resourceClusterStateRefreshFunc(c, d.Id()),
+		Refresh:    resourceClusterStateRefreshFunc(ctx, c, d, machinePoolStatusOpenStack),
 		Timeout:    d.Timeout(schema.TimeoutCreate) - 1*time.Minute,
 		MinTimeout: 10 * time.Second,
 		Delay:      30 * time.Second,
@@ -386,14 +470,14 @@ func toOpenStackCluster(d *schema.ResourceData) *models.V1SpectroOpenStackCluste
 
 
 //goland:noinspection GoUnhandledErrorResult
-func resourceClusterOpenStackRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceClusterOpenStackRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 
 	uid := d.Id()
 
-	cluster, err := c.GetCluster(uid)
+	cluster, err := c.GetCluster(ctx, uid)
 	if err != nil {
 		return diag.FromErr(err)
 	} else if cluster == nil {
@@ -402,32 +486,55 @@ func resourceClusterOpenStackRead(_ context.Context, d *schema.ResourceData, m i
 		return diags
 	}
 
+	if cluster.Spec.CloudConfigRef == nil {
+		return errCloudConfigNotFound(d, "")
+	}
 	configUID := cluster.Spec.CloudConfigRef.UID
 	d.Set("cloud_config_id", configUID)
 
-	if err := d.Set("tags", flattenTags(cluster.Metadata.Labels)); err != nil {
+	if err := flattenCommonAttributes(d, cluster); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := flattenOsPatchConfig(d, cluster.Spec.ClusterConfig); err != nil {
 		return diag.FromErr(err)
 	}
 
 	var config *models.V1OpenStackCloudConfig
-	if config, err = c.GetCloudConfigOpenStack(configUID); err != nil {
+	if config, err = c.GetCloudConfigOpenStack(ctx, configUID); err != nil {
+		return diag.FromErr(err)
+	} else if config == nil || config.Spec == nil {
+		return errCloudConfigNotFound(d, configUID)
+	}
+
+	if config.Spec.CloudAccountRef != nil {
+		if err := d.Set("cloud_account_id", config.Spec.CloudAccountRef.UID); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := d.Set("cloud_config", flattenClusterConfigOpenStack(d, config.Spec.ClusterConfig)); err != nil {
 		return diag.FromErr(err)
 	}
 
-	kubecfg, err := c.GetClusterKubeConfig(uid)
+	kubecfg, err := c.GetClusterKubeConfig(ctx, uid)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	if err := d.Set("kubeconfig", kubecfg); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := flattenClusterStatus(d, cluster, kubecfg); err != nil {
+		return diag.FromErr(err)
+	}
 
 	mp := flattenMachinePoolConfigsOpenStack(config.Spec.MachinePoolConfig)
+	if err := flattenMachinePoolStatus(ctx, c, d, configUID, mp, machinePoolStatusOpenStack); err != nil {
+		return diag.FromErr(err)
+	}
 	if err := d.Set("machine_pool", mp); err != nil {
 		return diag.FromErr(err)
 	}
 
-	if policy, err := c.GetClusterBackupConfig(d.Id()); err != nil {
+	if policy, err := c.GetClusterBackupConfig(ctx, d.Id()); err != nil {
 		return diag.FromErr(err)
 	} else if policy != nil && policy.Spec.Config != nil {
 		if err := d.Set("backup_policy", flattenBackupPolicy(policy.Spec.Config)); err != nil {
@@ -435,7 +542,7 @@ func resourceClusterOpenStackRead(_ context.Context, d *schema.ResourceData, m i
 		}
 	}
 
-	if policy, err := c.GetClusterScanConfig(d.Id()); err != nil {
+	if policy, err := c.GetClusterScanConfig(ctx, d.Id()); err != nil {
 		return diag.FromErr(err)
 	} else if policy != nil && policy.Spec.DriverSpec != nil {
 		if err := d.Set("scan_policy", flattenScanPolicy(policy.Spec.DriverSpec)); err != nil {
@@ -446,6 +553,32 @@ func resourceClusterOpenStackRead(_ context.Context, d *schema.ResourceData, m i
 	return diags
 }
 
+func flattenClusterConfigOpenStack(d *schema.ResourceData, config *models.V1OpenStackClusterConfig) []interface{} {
+	if config == nil {
+		return d.Get("cloud_config").([]interface{})
+	}
+
+	cloudConfig := make(map[string]interface{})
+	cloudConfig["region"] = config.Region
+	cloudConfig["ssh_key"] = config.SSHKeyName
+	cloudConfig["subnet_cidr"] = config.NodeCidr
+	cloudConfig["dns_servers"] = config.DNSNameservers
+	if config.Domain != nil {
+		cloudConfig["domain"] = config.Domain.Name
+	}
+	if config.Project != nil {
+		cloudConfig["project"] = config.Project.Name
+	}
+	if config.Network != nil {
+		cloudConfig["network_id"] = config.Network.ID
+	}
+	if config.Subnet != nil {
+		cloudConfig["subnet_id"] = config.Subnet.ID
+	}
+
+	return []interface{}{cloudConfig}
+}
+
 func flattenMachinePoolConfigsOpenStack(machinePools []*models.V1OpenStackMachinePoolConfig) []interface{} {
 
 	if machinePools == nil {
@@ -467,6 +600,7 @@ func flattenMachinePoolConfigsOpenStack(machinePools []*models.V1OpenStackMachin
 		oi["azs"] = machinePool.Azs
 		oi["instance_type"] = machinePool.FlavorConfig.Name
 
+		flattenNodePoolMetadata(oi, machinePool.AdditionalLabels, machinePool.AdditionalTags, machinePool.Taints)
 		ois = append(ois, oi)
 	}
 
@@ -479,75 +613,32 @@ func resourceClusterOpenStackUpdate(ctx context.Context, d *schema.ResourceData,
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
-	cloudConfigId := d.Get("cloud_config_id").(string)
-
 	if d.HasChange("machine_pool") {
-		oraw, nraw := d.GetChange("machine_pool")
-		if oraw == nil {
-			oraw = new(schema.Set)
-		}
-		if nraw == nil {
-			nraw = new(schema.Set)
-		}
-
-		os := oraw.([]interface{})
-		ns := nraw.([]interface{})
-
-		osMap := make(map[string]interface{})
-		for _, mp := range os {
-			machinePool := mp.(map[string]interface{})
-			osMap[machinePool["name"].(string)] = machinePool
-		}
-
-		for _, mp := range ns {
-			machinePoolResource := mp.(map[string]interface{})
-			name := machinePoolResource["name"].(string)
-			hash := resourceMachinePoolOpenStackHash(machinePoolResource)
-
-			machinePool := toMachinePoolOpenStack(machinePoolResource)
-
-			var err error
-			if oldMachinePool, ok := osMap[name]; !ok {
-				log.Printf("Create machine pool %s", name)
-				err = c.CreateMachinePoolOpenStack(cloudConfigId, machinePool)
-			} else if hash != resourceMachinePoolOpenStackHash(oldMachinePool) {
-				log.Printf("Change in machine pool %s", name)
-				err = c.UpdateMachinePoolOpenStack(cloudConfigId, machinePool)
-			}
-
-			if err != nil {
-				return diag.FromErr(err)
-			}
-
-			// Processed (if exists)
-			delete(osMap, name)
-		}
-
-		// Deleted old machine pools
-		for _, mp := range osMap {
-			machinePool := mp.(map[string]interface{})
-			name := machinePool["name"].(string)
-			log.Printf("Deleted machine pool %s", name)
-			if err := c.DeleteMachinePoolOpenStack(cloudConfigId, name); err != nil {
-				return diag.FromErr(err)
-			}
+		if err := updateMachinePools(ctx, c, d, machinePoolAdapterOpenStack); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	if d.HasChanges("cluster_profile") {
-		if err := updateProfiles(c, d); err != nil {
+		if err := updateProfiles(ctx, c, d); err != nil {
 			return diag.FromErr(err)
 		}
 	}
 
 	if d.HasChange("backup_policy") {
-		if err := updateBackupPolicy(c, d); err != nil {
+		if err := updateBackupPolicy(ctx, c, d); err != nil {
 			return diag.FromErr(err)
 		}
 	}
 
 	if d.HasChange("scan_policy") {
-		if err := updateScanPolicy(c, d); err != nil {
+		if err := updateScanPolicy(ctx, c, d); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChanges("machine_pool", "cluster_profile") {
+		if err := waitForClusterUpdate(ctx, c, d, machinePoolStatusOpenStack); err != nil {
 			return diag.FromErr(err)
 		}
 	}
@@ -557,6 +648,32 @@ func resourceClusterOpenStackUpdate(ctx context.Context, d *schema.ResourceData,
 	return diags
 }
 
+var machinePoolAdapterOpenStack = machinePoolAdapter{
+	hash: resourceMachinePoolOpenStackHash,
+	create: func(ctx context.Context, c *client.V1Client, cloudConfigId string, machinePool map[string]interface{}) error {
+		return c.CreateMachinePoolOpenStack(ctx, cloudConfigId, toMachinePoolOpenStack(machinePool))
+	},
+	update: func(ctx context.Context, c *client.V1Client, cloudConfigId string, _, machinePool map[string]interface{}) error {
+		return c.UpdateMachinePoolOpenStack(ctx, cloudConfigId, toMachinePoolOpenStack(machinePool))
+	},
+	delete: func(ctx context.Context, c *client.V1Client, cloudConfigId string, name string) error {
+		return c.DeleteMachinePoolOpenStack(ctx, cloudConfigId, name)
+	},
+}
+
+func machinePoolStatusOpenStack(ctx context.Context, c *client.V1Client, cloudConfigId, machinePoolName string) ([]*models.V1CloudMachineStatus, error) {
+	machines, err := c.GetMachinePoolMachinesOpenStack(ctx, cloudConfigId, machinePoolName)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*models.V1CloudMachineStatus, len(machines))
+	for i, machine := range machines {
+		statuses[i] = machine.Status
+	}
+	return statuses, nil
+}
+
 
 func toMachinePoolOpenStack(machinePool interface{}) *models.V1OpenStackMachinePoolConfigEntity {
 	m := machinePool.(map[string]interface{})
@@ -584,10 +701,13 @@ func toMachinePoolOpenStack(machinePool interface{}) *models.V1OpenStackMachineP
 			},
 		},
 		PoolConfig: &models.V1MachinePoolConfigEntity{
-			IsControlPlane: controlPlane,
-			Labels:         labels,
-			Name:           ptr.StringPtr(m["name"].(string)),
-			Size:           ptr.Int32Ptr(int32(m["count"].(int))),
+			AdditionalLabels: toAdditionalNodePoolLabels(m),
+			AdditionalTags:   toAdditionalNodePoolTags(m),
+			Taints:           toNodePoolTaints(m),
+			IsControlPlane:   controlPlane,
+			Labels:           labels,
+			Name:             ptr.StringPtr(m["name"].(string)),
+			Size:             ptr.Int32Ptr(int32(m["count"].(int))),
 			UpdateStrategy: &models.V1UpdateStrategy{
 				Type: m["update_strategy"].(string),
 			},
@@ -7,10 +7,14 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"log"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/spectrocloud/gomi/pkg/ptr"
 	"github.com/spectrocloud/hapi/models"
@@ -23,6 +27,14 @@ func resourceClusterProfile() *schema.Resource {
 		ReadContext:   resourceClusterProfileRead,
 		UpdateContext: resourceClusterProfileUpdate,
 		DeleteContext: resourceClusterProfileDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceClusterProfileImport,
+		},
+		CustomizeDiff: customdiff.All(
+			resourceClusterProfileCustomizeDiff,
+			resourceCloudPacksCustomizeDiff,
+			resourceManifestsCustomizeDiff,
+		),
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(20 * time.Second),
@@ -35,6 +47,20 @@ func resourceClusterProfile() *schema.Resource {
 				Type:     schema.TypeString,
 				Required: true,
 			},
+			"version": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validation.StringMatch(profileVersionRegexp, "must be a version of the form major.minor.patch"),
+				DiffSuppressFunc: suppressOlderProfileVersion,
+			},
+			"version_ids": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 			"tags": {
 				Type:     schema.TypeSet,
 				Optional: true,
@@ -75,6 +101,11 @@ func resourceClusterProfile() *schema.Resource {
 							Computed: true,
 							Optional: true,
 						},
+						"registry_uid": {
+							Type:     schema.TypeString,
+							Computed: true,
+							Optional: true,
+						},
 						"name": {
 							Type:     schema.TypeString,
 							Required: true,
@@ -93,15 +124,21 @@ func resourceClusterProfile() *schema.Resource {
 										Required: true,
 									},
 									"content": {
+										Type:             schema.TypeString,
+										Optional:         true,
+										DiffSuppressFunc: suppressYamlDiff,
+									},
+									"content_hash": {
 										Type:     schema.TypeString,
-										Required: true,
-										DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-											// UI strips the trailing newline on save
-											if strings.TrimSpace(old) == strings.TrimSpace(new) {
-												return true
-											}
-											return false
-										},
+										Computed: true,
+									},
+									"file": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"directory": {
+										Type:     schema.TypeString,
+										Optional: true,
 									},
 								},
 							},
@@ -115,15 +152,9 @@ func resourceClusterProfile() *schema.Resource {
 							Optional: true,
 						},
 						"values": {
-							Type:     schema.TypeString,
-							Optional: true,
-							DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-								// UI strips the trailing newline on save
-								if strings.TrimSpace(old) == strings.TrimSpace(new) {
-									return true
-								}
-								return false
-							},
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: suppressYamlDiff,
 						},
 					},
 				},
@@ -132,6 +163,45 @@ func resourceClusterProfile() *schema.Resource {
 	}
 }
 
+// DefaultClusterProfileVersion is the version of cluster profiles that do not set one
+var DefaultClusterProfileVersion = "1.0.0"
+
+var profileVersionRegexp = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+
+// resourceClusterProfileCustomizeDiff plans a new version of the profile when
+// its packs or the files of its manifests change, so that clusters can move to
+// it one by one. The patch version is bumped unless a new version is set.
+func resourceClusterProfileCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || (!d.HasChange("pack") && !d.HasChange("version") && !manifestFilesChanged(d)) {
+		return nil
+	}
+
+	if !d.HasChange("version") {
+		version, _ := d.GetChange("version")
+		if err := d.SetNew("version", nextProfileVersion(version.(string))); err != nil {
+			return err
+		}
+	}
+	return d.SetNewComputed("version_ids")
+}
+
+// suppressOlderProfileVersion ignores a configured version below the one of
+// the profile, which is the case once packs changed and the version was bumped
+func suppressOlderProfileVersion(k, old, new string, d *schema.ResourceData) bool {
+	return old != "" && new != "" && compareProfileVersions(new, old) < 0
+}
+
+// nextProfileVersion bumps the patch of a major.minor.patch version
+func nextProfileVersion(version string) string {
+	parts := strings.Split(version, ".")
+	patch, err := strconv.Atoi(parts[len(parts)-1])
+	if len(parts) != 3 || err != nil {
+		return DefaultClusterProfileVersion
+	}
+	parts[2] = strconv.Itoa(patch + 1)
+	return strings.Join(parts, ".")
+}
+
 func resourceClusterProfileCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
@@ -144,26 +214,29 @@ func resourceClusterProfileCreate(ctx context.Context, d *schema.ResourceData, m
 	}
 
 	// Create
-	uid, err := c.CreateClusterProfile(clusterProfile)
+	uid, err := c.CreateClusterProfile(ctx, clusterProfile)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	// And then publish
-	if err = c.PublishClusterProfile(uid); err != nil {
+	if err = c.PublishClusterProfile(ctx, uid); err != nil {
 		return diag.FromErr(err)
 	}
 	d.SetId(uid)
+	if err := d.Set("version_ids", map[string]interface{}{clusterProfile.Spec.Version: uid}); err != nil {
+		return diag.FromErr(err)
+	}
 	resourceClusterProfileRead(ctx, d, m)
 	return diags
 }
 
-func resourceClusterProfileRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceClusterProfileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 
-	cp, err := c.GetClusterProfile(d.Id())
+	cp, err := c.GetClusterProfile(ctx, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	} else if cp == nil {
@@ -175,30 +248,26 @@ func resourceClusterProfileRead(_ context.Context, d *schema.ResourceData, m int
 	if err := d.Set("tags", flattenTags(cp.Metadata.Labels)); err != nil {
 		return diag.FromErr(err)
 	}
-
-	// make a map of all the content
-	packManifests := make(map[string][]string)
-	for _, p := range cp.Spec.Published.Packs {
-		if len(p.Manifests) > 0 {
-			content, err := c.GetClusterProfileManifestPack(d.Id(), p.PackUID)
-			if err != nil {
-				return diag.FromErr(err)
-			}
-
-			if len(content) > 0 {
-				// TODO at some point support multiple manifests... or I hope it's just returned in
-				// the original call
-				c := make([]string, len(content))
-				for i, co := range content {
-					c[i] = co.Spec.Published.Content
-				}
-				packManifests[p.PackUID] = c
-			}
+	if v, found := cp.Metadata.Annotations["description"]; found {
+		if err := d.Set("description", v); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
+	packManifests, err := getPackManifests(ctx, c, d.Id(), cp.Spec.Published.Packs)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	_ = d.Set("name", cp.Metadata.Name)
-	packs := flattenPacks(cp.Spec.Published.Packs, packManifests)
+	_ = d.Set("version", cp.Spec.Version)
+	if len(d.Get("version_ids").(map[string]interface{})) == 0 {
+		// imported, or created before versions were tracked
+		_ = d.Set("version_ids", map[string]interface{}{cp.Spec.Version: d.Id()})
+	}
+	_ = d.Set("cloud", string(cp.Spec.Published.CloudType))
+	_ = d.Set("type", string(cp.Spec.Published.Type))
+	packs := flattenPacks(cp.Spec.Published.Packs, packManifests, d.Get("pack").([]interface{}))
 	if err := d.Set("pack", packs); err != nil {
 		return diag.FromErr(err)
 	}
@@ -206,30 +275,111 @@ func resourceClusterProfileRead(_ context.Context, d *schema.ResourceData, m int
 	return diags
 }
 
-func flattenPacks(packs []*models.V1PackRef, manifestContent map[string][]string) []interface{} {
+func resourceClusterProfileImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*client.V1Client)
+
+	uid, err := resolveProjectImportID(ctx, c, d.Id(), func(name string) (string, error) {
+		profiles, err := c.GetClusterProfiles(ctx)
+		if err != nil {
+			return "", err
+		}
+		if profile := findClusterProfile(profiles, name, ""); profile != nil {
+			return profile.Metadata.UID, nil
+		}
+		return "", fmt.Errorf("cluster profile '%s' not found", name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(uid)
+	return []*schema.ResourceData{d}, nil
+}
+
+// findClusterProfile returns the given version of the named cluster profile,
+// or its latest version when version is empty
+func findClusterProfile(profiles []*models.V1ClusterProfile, name, version string) *models.V1ClusterProfile {
+	var found *models.V1ClusterProfile
+	for _, profile := range profiles {
+		if profile.Metadata.Name != name {
+			continue
+		}
+		if version != "" {
+			if profile.Spec.Version == version {
+				return profile
+			}
+		} else if found == nil || compareProfileVersions(profile.Spec.Version, found.Spec.Version) > 0 {
+			found = profile
+		}
+	}
+	return found
+}
+
+// compareProfileVersions compares two major.minor.patch versions numerically
+func compareProfileVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		ai, _ := strconv.Atoi(as[i])
+		bi, _ := strconv.Atoi(bs[i])
+		if ai != bi {
+			return ai - bi
+		}
+	}
+	return len(as) - len(bs)
+}
+
+// flattenPacks flattens the packs of a cluster profile with the content of
+// their manifests. Manifests are matched by name and keep the order and the
+// file or directory they have in oldPacks, those loaded from a file or
+// directory only keep the hash of their content.
+func flattenPacks(packs []*models.V1PackRef, manifestContent map[string]map[string]string, oldPacks []interface{}) []interface{} {
 	if packs == nil {
 		return make([]interface{}, 0)
 	}
 
+	oldManifests := make(map[string][]interface{})
+	for _, p := range oldPacks {
+		pack := p.(map[string]interface{})
+		oldManifests[pack["name"].(string)], _ = pack["manifest"].([]interface{})
+	}
+
 	ps := make([]interface{}, len(packs))
 	for i, pack := range packs {
 		p := make(map[string]interface{})
 
 		p["uid"] = pack.PackUID
+		p["registry_uid"] = pack.RegistryUID
 		p["name"] = *pack.Name
 		p["tag"] = pack.Tag
 		p["values"] = pack.Values
 		p["type"] = pack.Type
 
-		if _, ok := manifestContent[pack.PackUID]; ok {
-			ma := make([]interface{}, len(pack.Manifests))
-			for j, m := range pack.Manifests {
+		if content, ok := manifestContent[pack.PackUID]; ok {
+			old := oldManifests[*pack.Name]
+			sources := make(map[string]map[string]interface{}, len(old))
+			for _, m := range old {
+				om := m.(map[string]interface{})
+				sources[om["name"].(string)] = om
+			}
+
+			ma := make([]interface{}, 0, len(pack.Manifests))
+			for _, m := range sortManifestRefs(pack.Manifests, old) {
 				mj := make(map[string]interface{})
 				mj["name"] = m.Name
 				mj["uid"] = m.UID
-				mj["content"] = manifestContent[pack.PackUID][j]
+				mj["content"] = content[m.Name]
+				if om, ok := sources[m.Name]; ok {
+					file, _ := om["file"].(string)
+					directory, _ := om["directory"].(string)
+					if file != "" || directory != "" {
+						mj["content"] = ""
+						mj["content_hash"] = hashManifestContent(content[m.Name])
+					}
+					mj["file"] = file
+					mj["directory"] = directory
+				}
 
-				ma[j] = mj
+				ma = append(ma, mj)
 			}
 
 			p["manifest"] = ma
@@ -246,16 +396,44 @@ func resourceClusterProfileUpdate(ctx context.Context, d *schema.ResourceData, m
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
-	if d.HasChanges("name") || d.HasChanges("pack") {
-		log.Printf("Updating packs")
+	if d.HasChange("version") {
+		// Earlier versions are left as is for the clusters still using them
+		log.Printf("Creating version %s", d.Get("version").(string))
+		clusterProfile, err := toClusterProfileCreate(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		clusterProfile.Metadata.UID = ""
+
+		uid, err := c.CreateClusterProfile(ctx, clusterProfile)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if err = c.PublishClusterProfile(ctx, uid); err != nil {
+			return diag.FromErr(err)
+		}
+
+		oldVersionIDs, _ := d.GetChange("version_ids")
+		versionIDs := make(map[string]interface{})
+		for version, id := range oldVersionIDs.(map[string]interface{}) {
+			versionIDs[version] = id
+		}
+		versionIDs[clusterProfile.Spec.Version] = uid
+		d.SetId(uid)
+		if err := d.Set("version_ids", versionIDs); err != nil {
+			return diag.FromErr(err)
+		}
+	} else if d.HasChanges("name", "tags", "description") {
+		// Earlier versions keep the metadata they were created with
+		log.Printf("Updating cluster profile metadata")
 		cluster, err := toClusterProfileUpdate(d)
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		if err := c.UpdateClusterProfile(cluster); err != nil {
+		if err := c.UpdateClusterProfile(ctx, cluster); err != nil {
 			return diag.FromErr(err)
 		}
-		if err := c.PublishClusterProfile(cluster.Metadata.UID); err != nil {
+		if err := c.PublishClusterProfile(ctx, cluster.Metadata.UID); err != nil {
 			return diag.FromErr(err)
 		}
 	}
@@ -265,14 +443,39 @@ func resourceClusterProfileUpdate(ctx context.Context, d *schema.ResourceData, m
 	return diags
 }
 
-func resourceClusterProfileDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceClusterProfileDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 
 	var diags diag.Diagnostics
 
-	err := c.DeleteClusterProfile(d.Id())
-	if err != nil {
-		return diag.FromErr(err)
+	// every version created by the resource is deleted, the latest first
+	versionIDs := d.Get("version_ids").(map[string]interface{})
+	versions := make([]string, 0, len(versionIDs))
+	for version := range versionIDs {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return compareProfileVersions(versions[i], versions[j]) > 0
+	})
+
+	uids := []string{d.Id()}
+	for _, version := range versions {
+		if uid := versionIDs[version].(string); uid != d.Id() {
+			uids = append(uids, uid)
+		}
+	}
+
+	for _, uid := range uids {
+		if uid != d.Id() {
+			if cp, err := c.GetClusterProfile(ctx, uid); err != nil {
+				return diag.FromErr(err)
+			} else if cp == nil {
+				continue
+			}
+		}
+		if err := c.DeleteClusterProfile(ctx, uid); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	return diags
@@ -281,17 +484,22 @@ func resourceClusterProfileDelete(_ context.Context, d *schema.ResourceData, m i
 func toClusterProfileCreate(d *schema.ResourceData) (*models.V1ClusterProfileEntity, error) {
 	cp := &models.V1ClusterProfileEntity{
 		Metadata: &models.V1ObjectMeta{
-			Name:   d.Get("name").(string),
-			UID:    d.Id(),
-			Labels: toTags(d),
+			Name:        d.Get("name").(string),
+			UID:         d.Id(),
+			Labels:      toTags(d),
+			Annotations: toClusterProfileAnnotations(d),
 		},
 		Spec: &models.V1ClusterProfileEntitySpec{
 			Template: &models.V1ClusterProfileTemplateDraft{
 				CloudType: models.V1CloudType(d.Get("cloud").(string)),
 				Type:      models.V1ProfileType(d.Get("type").(string)),
 			},
+			Version: d.Get("version").(string),
 		},
 	}
+	if cp.Spec.Version == "" {
+		cp.Spec.Version = DefaultClusterProfileVersion
+	}
 
 	packs := make([]*models.V1PackManifestEntity, 0)
 	for _, pack := range d.Get("pack").([]interface{}) {
@@ -306,6 +514,14 @@ func toClusterProfileCreate(d *schema.ResourceData) (*models.V1ClusterProfileEnt
 	return cp, nil
 }
 
+func toClusterProfileAnnotations(d *schema.ResourceData) map[string]string {
+	annotations := make(map[string]string)
+	if len(d.Get("description").(string)) > 0 {
+		annotations["description"] = d.Get("description").(string)
+	}
+	return annotations
+}
+
 func toClusterProfilePackCreate(pSrc interface{}) (*models.V1PackManifestEntity, error) {
 	p := pSrc.(map[string]interface{})
 
@@ -326,10 +542,11 @@ func toClusterProfilePackCreate(pSrc interface{}) (*models.V1PackManifestEntity,
 	}
 
 	pack := &models.V1PackManifestEntity{
-		Name: ptr.StringPtr(pName),
-		Tag:  p["tag"].(string),
-		UID:  pUID,
-		Type: pType,
+		Name:        ptr.StringPtr(pName),
+		Tag:         p["tag"].(string),
+		UID:         pUID,
+		RegistryUID: p["registry_uid"].(string),
+		Type:        pType,
 		// UI strips a single newline, so we should do the same
 		Values: strings.TrimSpace(p["values"].(string)),
 	}
@@ -337,8 +554,12 @@ func toClusterProfilePackCreate(pSrc interface{}) (*models.V1PackManifestEntity,
 	manifests := make([]*models.V1ManifestInputEntity, 0)
 	for _, manifest := range p["manifest"].([]interface{}) {
 		m := manifest.(map[string]interface{})
+		content, err := toManifestContent(m)
+		if err != nil {
+			return nil, err
+		}
 		manifests = append(manifests, &models.V1ManifestInputEntity{
-			Content: strings.TrimSpace(m["content"].(string)),
+			Content: content,
 			Name:    m["name"].(string),
 		})
 	}
@@ -350,8 +571,10 @@ func toClusterProfilePackCreate(pSrc interface{}) (*models.V1PackManifestEntity,
 func toClusterProfileUpdate(d *schema.ResourceData) (*models.V1ClusterProfileUpdateEntity, error) {
 	cp := &models.V1ClusterProfileUpdateEntity{
 		Metadata: &models.V1ObjectMeta{
-			Name: d.Get("name").(string),
-			UID:  d.Id(),
+			Name:        d.Get("name").(string),
+			UID:         d.Id(),
+			Labels:      toTags(d),
+			Annotations: toClusterProfileAnnotations(d),
 		},
 		Spec: &models.V1ClusterProfileUpdateEntitySpec{
 			Template: &models.V1ClusterProfileTemplateUpdate{
@@ -360,9 +583,21 @@ func toClusterProfileUpdate(d *schema.ResourceData) (*models.V1ClusterProfileUpd
 		},
 	}
 
+	// manifests keep their uid by name, whatever their position in the list
+	manifestUIDs := make(map[string]string)
+	oldPacks, _ := d.GetChange("pack")
+	for _, p := range oldPacks.([]interface{}) {
+		pack := p.(map[string]interface{})
+		manifests, _ := pack["manifest"].([]interface{})
+		for _, m := range manifests {
+			manifest := m.(map[string]interface{})
+			manifestUIDs[pack["name"].(string)+"/"+manifest["name"].(string)] = manifest["uid"].(string)
+		}
+	}
+
 	packs := make([]*models.V1PackManifestUpdateEntity, 0)
 	for _, pack := range d.Get("pack").([]interface{}) {
-		if p, e := toClusterProfilePackUpdate(pack); e != nil {
+		if p, e := toClusterProfilePackUpdate(pack, manifestUIDs); e != nil {
 			return nil, e
 		} else {
 			packs = append(packs, p)
@@ -373,7 +608,7 @@ func toClusterProfileUpdate(d *schema.ResourceData) (*models.V1ClusterProfileUpd
 	return cp, nil
 }
 
-func toClusterProfilePackUpdate(pSrc interface{}) (*models.V1PackManifestUpdateEntity, error) {
+func toClusterProfilePackUpdate(pSrc interface{}, manifestUIDs map[string]string) (*models.V1PackManifestUpdateEntity, error) {
 	p := pSrc.(map[string]interface{})
 
 	pName := p["name"].(string)
@@ -394,21 +629,28 @@ func toClusterProfilePackUpdate(pSrc interface{}) (*models.V1PackManifestUpdateE
 
 	pack := &models.V1PackManifestUpdateEntity{
 		//Layer:  p["layer"].(string),
-		Name: ptr.StringPtr(pName),
-		Tag:  p["tag"].(string),
-		UID:  pUID,
-		Type: pType,
+		Name:        ptr.StringPtr(pName),
+		Tag:         p["tag"].(string),
+		UID:         pUID,
+		RegistryUID: p["registry_uid"].(string),
+		Type:        pType,
 		// UI strips a single newline, so we should do the same
 		Values: strings.TrimSpace(p["values"].(string)),
 	}
 
+	// manifests left out are removed, new ones are sent without uid
 	manifests := make([]*models.V1ManifestRefUpdateEntity, 0)
 	for _, manifest := range p["manifest"].([]interface{}) {
 		m := manifest.(map[string]interface{})
+		content, err := toManifestContent(m)
+		if err != nil {
+			return nil, err
+		}
+		mName := m["name"].(string)
 		manifests = append(manifests, &models.V1ManifestRefUpdateEntity{
-			Content: strings.TrimSpace(m["content"].(string)),
-			Name:    ptr.StringPtr(m["name"].(string)),
-			UID:     m["uid"].(string),
+			Content: content,
+			Name:    ptr.StringPtr(mName),
+			UID:     manifestUIDs[pName+"/"+mName],
 		})
 	}
 	pack.Manifests = manifests
@@ -3,6 +3,7 @@ package spectrocloud
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-cty/cty"
@@ -20,6 +21,9 @@ func resourcePrivateCloudGatewayIpPool() *schema.Resource {
 		ReadContext:   resourceIpPoolRead,
 		UpdateContext: resourceIpPoolUpdate,
 		DeleteContext: resourceIpPoolDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceIpPoolImport,
+		},
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(10 * time.Minute),
@@ -96,7 +100,7 @@ func resourceIpPoolCreate(ctx context.Context, d *schema.ResourceData, m interfa
 
 	pool := toIpPool(d)
 
-	uid, err := c.CreateIpPool(pcgUID, pool)
+	uid, err := c.CreateIpPool(ctx, pcgUID, pool)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -111,7 +115,7 @@ func resourceIpPoolRead(ctx context.Context, d *schema.ResourceData, m interface
 
 	pcgUID := d.Get("private_cloud_gateway_id").(string)
 
-	pool, err := c.GetIpPool(pcgUID, d.Id())
+	pool, err := c.GetIpPool(ctx, pcgUID, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	} else if pool == nil {
@@ -134,10 +138,16 @@ func resourceIpPoolRead(ctx context.Context, d *schema.ResourceData, m interface
 	}
 
 	if len(pool.Spec.Pool.Subnet) > 0 {
+		if err := d.Set("network_type", "subnet"); err != nil {
+			return diag.FromErr(err)
+		}
 		if err := d.Set("subnet_cidr", pool.Spec.Pool.Subnet); err != nil {
 			return diag.FromErr(err)
 		}
 	} else {
+		if err := d.Set("network_type", "range"); err != nil {
+			return diag.FromErr(err)
+		}
 		if err := d.Set("ip_start_range", pool.Spec.Pool.Start); err != nil {
 			return diag.FromErr(err)
 		}
@@ -159,6 +169,21 @@ func resourceIpPoolRead(ctx context.Context, d *schema.ResourceData, m interface
 	return diags
 }
 
+// resourceIpPoolImport accepts ids of the form <pcg_uid>:<pool_uid>
+func resourceIpPoolImport(_ context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid ip pool id '%s', expected <private_cloud_gateway_id>:<ip_pool_id>", d.Id())
+	}
+
+	if err := d.Set("private_cloud_gateway_id", parts[0]); err != nil {
+		return nil, err
+	}
+	d.SetId(parts[1])
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceIpPoolUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
@@ -167,7 +192,7 @@ func resourceIpPoolUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 
 	pool := toIpPool(d)
 
-	err := c.UpdateIpPool(pcgUID, d.Id(), pool)
+	err := c.UpdateIpPool(ctx, pcgUID, d.Id(), pool)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -181,7 +206,7 @@ func resourceIpPoolDelete(ctx context.Context, d *schema.ResourceData, m interfa
 
 	pcgUID := d.Get("private_cloud_gateway_id").(string)
 
-	err := c.DeleteIpPool(pcgUID, d.Id())
+	err := c.DeleteIpPool(ctx, pcgUID, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
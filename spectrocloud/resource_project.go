@@ -18,6 +18,9 @@ func resourceProject() *schema.Resource {
 		ReadContext:   resourceProjectRead,
 		UpdateContext: resourceProjectUpdate,
 		DeleteContext: resourceProjectDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceProjectImport,
+		},
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(10 * time.Minute),
@@ -52,7 +55,7 @@ func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, m interf
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
 
-	uid, err := c.CreateProject(toProject(d))
+	uid, err := c.CreateProject(ctx, toProject(d))
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -65,7 +68,7 @@ func resourceProjectRead(ctx context.Context, d *schema.ResourceData, m interfac
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
 
-	project, err := c.GetProject(d.Id())
+	project, err := c.GetProject(ctx, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	} else if project == nil {
@@ -91,11 +94,25 @@ func resourceProjectRead(ctx context.Context, d *schema.ResourceData, m interfac
 	return diags
 }
 
+func resourceProjectImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*client.V1Client)
+
+	uid, err := resolveImportID(d.Id(), func(name string) (string, error) {
+		return c.GetProjectUID(ctx, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(uid)
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceProjectUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
 
-	err := c.UpdateProject(d.Id(), toProject(d))
+	err := c.UpdateProject(ctx, d.Id(), toProject(d))
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -106,7 +123,7 @@ func resourceProjectDelete(ctx context.Context, d *schema.ResourceData, m interf
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
 
-	err := c.DeleteProject(d.Id())
+	err := c.DeleteProject(ctx, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -21,6 +21,9 @@ func resourceRegistryOciEcr() *schema.Resource {
 		ReadContext:   resourceRegistryEcrRead,
 		UpdateContext: resourceRegistryEcrUpdate,
 		DeleteContext: resourceRegistryEcrDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRegistryEcrImport,
+		},
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(10 * time.Minute),
@@ -79,7 +82,7 @@ func resourceRegistryEcrCreate(ctx context.Context, d *schema.ResourceData, m in
 	var diags diag.Diagnostics
 
 	registry := toRegistryEcr(d)
-	uid, err := c.CreateOciEcrRegistry(registry)
+	uid, err := c.CreateOciEcrRegistry(ctx, registry)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -92,7 +95,7 @@ func resourceRegistryEcrRead(ctx context.Context, d *schema.ResourceData, m inte
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
 
-	registry, err := c.GetRegistryOci(d.Id())
+	registry, err := c.GetRegistryOci(ctx, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	} else if registry == nil {
@@ -130,12 +133,30 @@ func resourceRegistryEcrRead(ctx context.Context, d *schema.ResourceData, m inte
 	return diags
 }
 
+func resourceRegistryEcrImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*client.V1Client)
+
+	uid, err := resolveImportID(d.Id(), func(name string) (string, error) {
+		registry, err := c.GetRegistryOciByName(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		return registry.Metadata.UID, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(uid)
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceRegistryEcrUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
 
 	registry := toRegistryEcr(d)
-	err := c.UpdateEcrRegistry(d.Id(), registry)
+	err := c.UpdateEcrRegistry(ctx, d.Id(), registry)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -146,7 +167,7 @@ func resourceRegistryEcrUpdate(ctx context.Context, d *schema.ResourceData, m in
 func resourceRegistryEcrDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
-	err := c.DeleteRegistry(d.Id())
+	err := c.DeleteRegistry(ctx, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -18,6 +18,9 @@ func resourceTeam() *schema.Resource {
 		ReadContext:   resourceTeamRead,
 		UpdateContext: resourceTeamUpdate,
 		DeleteContext: resourceTeamDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceTeamImport,
+		},
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(10 * time.Minute),
@@ -68,14 +71,14 @@ func resourceTeamCreate(ctx context.Context, d *schema.ResourceData, m interface
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
 
-	uid, err := c.CreateTeam(toTeam(d))
+	uid, err := c.CreateTeam(ctx, toTeam(d))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	d.SetId(uid)
 
 	//associate roles with team
-	err = c.AssociateTeamProjectRole(uid, toTeamProjectRoleMapping(d))
+	err = c.AssociateTeamProjectRole(ctx, uid, toTeamProjectRoleMapping(d))
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -88,7 +91,7 @@ func resourceTeamRead(ctx context.Context, d *schema.ResourceData, m interface{}
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
 
-	team, err := c.GetTeam(d.Id())
+	team, err := c.GetTeam(ctx, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	} else if team == nil {
@@ -104,7 +107,7 @@ func resourceTeamRead(ctx context.Context, d *schema.ResourceData, m interface{}
 		return diag.FromErr(err)
 	}
 
-	projectRoles, err := c.GetTeamProjectRoleAssociation(d.Id())
+	projectRoles, err := c.GetTeamProjectRoleAssociation(ctx, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -129,17 +132,35 @@ func resourceTeamRead(ctx context.Context, d *schema.ResourceData, m interface{}
 	return diags
 }
 
+func resourceTeamImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*client.V1Client)
+
+	uid, err := resolveImportID(d.Id(), func(name string) (string, error) {
+		team, err := c.GetTeamByName(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		return team.Metadata.UID, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(uid)
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceTeamUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
 
-	err := c.UpdateTeam(d.Id(), toTeam(d))
+	err := c.UpdateTeam(ctx, d.Id(), toTeam(d))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	//associate roles with team
-	err = c.AssociateTeamProjectRole(d.Id(), toTeamProjectRoleMapping(d))
+	err = c.AssociateTeamProjectRole(ctx, d.Id(), toTeamProjectRoleMapping(d))
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -151,7 +172,7 @@ func resourceTeamDelete(ctx context.Context, d *schema.ResourceData, m interface
 	c := m.(*client.V1Client)
 	var diags diag.Diagnostics
 
-	err := c.DeleteTeam(d.Id())
+	err := c.DeleteTeam(ctx, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -1,5 +1,14 @@
 package spectrocloud
 
+import (
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
+)
+
 func expandStringList(configured []interface{}) []string {
 	vs := make([]string, 0)
 	for _, v := range configured {
@@ -26,3 +35,39 @@ func stringContains(a []string, x string) bool {
 	}
 	return false
 }
+
+// suppressYamlDiff ignores changes to YAML that do not change the documents it
+// holds, eg key order, comments, quoting and whitespace normalised by the API.
+// Content that is not valid YAML is compared as trimmed text.
+func suppressYamlDiff(k, old, new string, d *schema.ResourceData) bool {
+	if strings.TrimSpace(old) == strings.TrimSpace(new) {
+		return true
+	}
+
+	oldDocs, err := decodeYamlDocuments(old)
+	if err != nil {
+		return false
+	}
+	newDocs, err := decodeYamlDocuments(new)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(oldDocs, newDocs)
+}
+
+// decodeYamlDocuments returns the non empty documents of a multi-document YAML stream
+func decodeYamlDocuments(content string) ([]interface{}, error) {
+	docs := make([]interface{}, 0)
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	for {
+		var doc interface{}
+		if err := decoder.Decode(&doc); err == io.EOF {
+			return docs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if doc != nil {
+			docs = append(docs, doc)
+		}
+	}
+}
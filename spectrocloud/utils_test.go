@@ -0,0 +1,69 @@
+package spectrocloud
+
+import "testing"
+
+func TestSuppressYamlDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		suppress bool
+	}{
+		{
+			name:     "reordered keys",
+			old:      "a: 1\nb:\n  c: 2\n  d: 3\n",
+			new:      "b:\n  d: 3\n  c: 2\na: 1\n",
+			suppress: true,
+		},
+		{
+			name:     "comments and whitespace",
+			old:      "# values\na: 1 # one\n\nb:    \"two\"\n",
+			new:      "a: 1\nb: two",
+			suppress: true,
+		},
+		{
+			name:     "document separators",
+			old:      "kind: Namespace\n---\nkind: Service\n",
+			new:      "---\nkind: Namespace\n---\n\nkind: Service\n---\n",
+			suppress: true,
+		},
+		{
+			name:     "documents merged",
+			old:      "a: 1\n---\nb: 2\n",
+			new:      "a: 1\nb: 2\n",
+			suppress: false,
+		},
+		{
+			name:     "documents reordered",
+			old:      "a: 1\n---\nb: 2\n",
+			new:      "b: 2\n---\na: 1\n",
+			suppress: false,
+		},
+		{
+			name:     "value changed",
+			old:      "a: 1\nb: 2\n",
+			new:      "a: 1\nb: 3\n",
+			suppress: false,
+		},
+		{
+			name:     "invalid YAML",
+			old:      "a: [1, 2\n",
+			new:      "a: [1,2\n",
+			suppress: false,
+		},
+		{
+			name:     "becomes invalid YAML",
+			old:      "a: 1\n",
+			new:      "a: 1\n b: [\n",
+			suppress: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suppressYamlDiff("values", tt.old, tt.new, nil); got != tt.suppress {
+				t.Errorf("suppressYamlDiff(%q, %q) = %t, want %t", tt.old, tt.new, got, tt.suppress)
+			}
+		})
+	}
+}